@@ -0,0 +1,62 @@
+package units
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrOverflow is returned when the result does not fit in Bytes.
+	ErrOverflow = errors.New("units: overflow")
+	// ErrInvalidAlignment is returned when the alignment is zero, or not a power of two where required.
+	ErrInvalidAlignment = errors.New("units: invalid alignment")
+)
+
+// IsPowerOfTwo reports whether b is a power of two, like page and sector sizes.
+func (b Bytes) IsPowerOfTwo() bool {
+	return b != 0 && b&(b-1) == 0
+}
+
+// IsAligned reports whether b is a multiple of align. A zero align is never satisfied.
+func (b Bytes) IsAligned(align Bytes) bool {
+	if align.IsPowerOfTwo() {
+		return b&(align-1) == 0
+	}
+	return align != 0 && b%align == 0
+}
+
+// AlignUp returns the least multiple of align greater than or equal to b.
+// align must be a power of two.
+func (b Bytes) AlignUp(align Bytes) (Bytes, error) {
+	if !align.IsPowerOfTwo() {
+		return 0, ErrInvalidAlignment
+	}
+	if b > math.MaxUint64-(align-1) {
+		return 0, ErrOverflow
+	}
+	return b.CeilBy(align), nil
+}
+
+// AlignDown returns the greatest multiple of align less than or equal to b.
+// align must be a power of two.
+func (b Bytes) AlignDown(align Bytes) (Bytes, error) {
+	if !align.IsPowerOfTwo() {
+		return 0, ErrInvalidAlignment
+	}
+	return b.FloorBy(align), nil
+}
+
+// AlignUpTo returns the least multiple of size greater than or equal to b.
+// Unlike AlignUp, size can be any non-zero value, like the width of a RAID stripe.
+func (b Bytes) AlignUpTo(size Bytes) (Bytes, error) {
+	if size == 0 {
+		return 0, ErrInvalidAlignment
+	}
+	if size.IsPowerOfTwo() {
+		return b.AlignUp(size)
+	}
+	if mod := b % size; mod != 0 && b > math.MaxUint64-(size-mod) {
+		return 0, ErrOverflow
+	}
+	return b.CeilBy(size), nil
+}
@@ -0,0 +1,115 @@
+package units
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes_IsPowerOfTwo(t *testing.T) {
+	for _, b := range []Bytes{1, 512, 4 * KiB, 2 * MiB, 1 << 63} {
+		assert.True(t, b.IsPowerOfTwo(), "%d", b)
+	}
+	for _, b := range []Bytes{0, 3, KB, 3 * MiB, math.MaxUint64} {
+		assert.False(t, b.IsPowerOfTwo(), "%d", b)
+	}
+}
+
+func TestBytes_IsAligned(t *testing.T) {
+	tests := []struct {
+		name  string
+		b     Bytes
+		align Bytes
+		want  bool
+	}{
+		{name: "zero", b: 0, align: 4 * KiB, want: true},
+		{name: "page", b: 8 * KiB, align: 4 * KiB, want: true},
+		{name: "page+1", b: 8*KiB + 1, align: 4 * KiB, want: false},
+		{name: "sector", b: 1536, align: 512, want: true},
+		{name: "stripe", b: 384 * KiB, align: 192 * KiB, want: true},
+		{name: "stripe+1", b: 384*KiB + 1, align: 192 * KiB, want: false},
+		{name: "zero align", b: 4 * KiB, align: 0, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.b.IsAligned(tt.align))
+		})
+	}
+}
+
+func TestBytes_AlignUp(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       Bytes
+		align   Bytes
+		want    Bytes
+		wantErr error
+	}{
+		{name: "zero", b: 0, align: 4 * KiB, want: 0},
+		{name: "aligned", b: 4 * KiB, align: 4 * KiB, want: 4 * KiB},
+		{name: "page+1", b: 4*KiB + 1, align: 4 * KiB, want: 8 * KiB},
+		{name: "sector", b: 513, align: 512, want: 1024},
+		{name: "hugepage", b: 3 * MiB, align: 2 * MiB, want: 4 * MiB},
+		{name: "max aligned", b: math.MaxUint64 - 4*KiB + 1, align: 4 * KiB, want: math.MaxUint64 - 4*KiB + 1},
+		{name: "overflow", b: math.MaxUint64 - 4*KiB + 2, align: 4 * KiB, wantErr: ErrOverflow},
+		{name: "not power of two", b: 1, align: 3 * KiB, wantErr: ErrInvalidAlignment},
+		{name: "zero align", b: 1, align: 0, wantErr: ErrInvalidAlignment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.b.AlignUp(tt.align)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBytes_AlignDown(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       Bytes
+		align   Bytes
+		want    Bytes
+		wantErr error
+	}{
+		{name: "zero", b: 0, align: 4 * KiB, want: 0},
+		{name: "aligned", b: 4 * KiB, align: 4 * KiB, want: 4 * KiB},
+		{name: "page-1", b: 4*KiB - 1, align: 4 * KiB, want: 0},
+		{name: "sector", b: 1023, align: 512, want: 512},
+		{name: "max", b: math.MaxUint64, align: 2 * MiB, want: math.MaxUint64 - 2*MiB + 1},
+		{name: "not power of two", b: 1, align: KB, wantErr: ErrInvalidAlignment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.b.AlignDown(tt.align)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBytes_AlignUpTo(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       Bytes
+		size    Bytes
+		want    Bytes
+		wantErr error
+	}{
+		{name: "zero", b: 0, size: 192 * KiB, want: 0},
+		{name: "stripe", b: 1, size: 192 * KiB, want: 192 * KiB},
+		{name: "stripe aligned", b: 384 * KiB, size: 192 * KiB, want: 384 * KiB},
+		{name: "stripe+1", b: 384*KiB + 1, size: 192 * KiB, want: 576 * KiB},
+		{name: "power of two", b: 4*KiB + 1, size: 4 * KiB, want: 8 * KiB},
+		{name: "overflow", b: math.MaxUint64 - 1, size: 10, wantErr: ErrOverflow},
+		{name: "zero size", b: 1, size: 0, wantErr: ErrInvalidAlignment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.b.AlignUpTo(tt.size)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrUnderflow is returned when the result is less than zero.
+var ErrUnderflow = errors.New("units: underflow")
+
+// AtomicBytes is Bytes which can be accessed atomically. The zero value is zero.
+//
+// It implements fmt.Formatter with the loaded value, so that it can be formatted like Bytes.
+type AtomicBytes struct {
+	v uint64
+}
+
+// Load atomically loads the value.
+func (a *AtomicBytes) Load() Bytes {
+	return Bytes(atomic.LoadUint64(&a.v))
+}
+
+// Store atomically stores b.
+func (a *AtomicBytes) Store(b Bytes) {
+	atomic.StoreUint64(&a.v, uint64(b))
+}
+
+// Swap atomically stores b and returns the old value.
+func (a *AtomicBytes) Swap(b Bytes) (old Bytes) {
+	return Bytes(atomic.SwapUint64(&a.v, uint64(b)))
+}
+
+// CompareAndSwap atomically stores new if the value is old, and reports whether it is stored.
+func (a *AtomicBytes) CompareAndSwap(old, new Bytes) (swapped bool) {
+	return atomic.CompareAndSwapUint64(&a.v, uint64(old), uint64(new))
+}
+
+// Add atomically adds delta and returns the new value.
+func (a *AtomicBytes) Add(delta Bytes) (new Bytes) {
+	return Bytes(atomic.AddUint64(&a.v, uint64(delta)))
+}
+
+// Sub atomically subtracts delta and returns the new value.
+// The value is kept and ErrUnderflow is returned if it is less than delta.
+func (a *AtomicBytes) Sub(delta Bytes) (new Bytes, err error) {
+	for {
+		old := a.Load()
+		if old < delta {
+			return old, ErrUnderflow
+		}
+		if a.CompareAndSwap(old, old-delta) {
+			return old - delta, nil
+		}
+	}
+}
+
+// Format implements fmt.Formatter by formatting the loaded value.
+func (a *AtomicBytes) Format(f fmt.State, verb rune) {
+	a.Load().Format(f, verb)
+}
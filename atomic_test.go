@@ -0,0 +1,59 @@
+package units
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicBytes(t *testing.T) {
+	var a AtomicBytes
+	assert.Equal(t, Bytes(0), a.Load())
+	a.Store(KiB)
+	assert.Equal(t, 2*KiB, a.Add(KiB))
+	assert.Equal(t, 2*KiB, a.Swap(MiB))
+	assert.False(t, a.CompareAndSwap(KiB, GiB))
+	assert.True(t, a.CompareAndSwap(MiB, GiB))
+	assert.Equal(t, GiB, a.Load())
+
+	got, err := a.Sub(GiB - MiB)
+	assert.NoError(t, err)
+	assert.Equal(t, MiB, got)
+	got, err = a.Sub(MiB + 1)
+	assert.Equal(t, ErrUnderflow, err)
+	assert.Equal(t, MiB, got)
+	assert.Equal(t, MiB, a.Load())
+}
+
+func TestAtomicBytes_concurrent(t *testing.T) {
+	var a AtomicBytes
+	a.Store(50 * KiB)
+	var wg sync.WaitGroup
+	var failed AtomicBytes
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			a.Add(KiB)
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := a.Sub(KiB); err != nil {
+				failed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 50*KiB+failed.Load()*KiB, a.Load())
+}
+
+func TestAtomicBytes_Format(t *testing.T) {
+	var a AtomicBytes
+	a.Store(KiB + 512)
+	assert.Equal(t, "1.5kiB", fmt.Sprintf("%f", &a))
+	assert.Equal(t, "1kiB", fmt.Sprintf("%s", &a))
+	assert.Equal(t, "1.5kB", fmt.Sprintf("%#f", &a))
+	assert.Equal(t, "1536", fmt.Sprintf("%d", &a))
+}
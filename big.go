@@ -0,0 +1,185 @@
+package units
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// bigUnit is a unit of the full IEC/SI ladder used by BigBytes.
+type bigUnit struct {
+	mag  *big.Int
+	name string
+	// verb formats to an integer number with this unit, zero if there is none
+	verb rune
+}
+
+var (
+	bigBinaryUnits  = newBigUnits(1024, "B", "kiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB", "RiB", "QiB")
+	bigDecimalUnits = newBigUnits(1000, "B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB", "RB", "QB")
+	bigUnitVerbs    = []rune{0, 'k', 'm', 'g', 't', 'p', 'e', 'z', 'y', 'r', 'q'}
+	// bigParseUnits maps lower case unit names to magnitudes, see Parse
+	bigParseUnits = map[string]*big.Int{"": big.NewInt(1)}
+)
+
+func newBigUnits(base int64, names ...string) []bigUnit {
+	units := make([]bigUnit, len(names))
+	mag := big.NewInt(1)
+	for i, name := range names {
+		units[i] = bigUnit{mag: new(big.Int).Set(mag), name: name}
+		mag.Mul(mag, big.NewInt(base))
+	}
+	return units
+}
+
+func init() {
+	for i := range bigBinaryUnits {
+		bigBinaryUnits[i].verb = bigUnitVerbs[i]
+		bigDecimalUnits[i].verb = bigUnitVerbs[i]
+		bigParseUnits[strings.ToLower(bigBinaryUnits[i].name)] = bigBinaryUnits[i].mag
+		bigParseUnits[strings.ToLower(bigDecimalUnits[i].name)] = bigDecimalUnits[i].mag
+		if i > 0 {
+			bigParseUnits[string(bigUnitVerbs[i])] = bigBinaryUnits[i].mag
+		}
+	}
+}
+
+// BigBytes is an arbitrary-precision size for values exceeding Bytes, like the capacity of fleets.
+//
+// It is formatted with the same verbs and flags as Bytes, but the unit is chosen from the full ladder
+// up to QiB (or QB), and there are more verbs for the larger units:
+//
+//	'p': PiB, 'e': EiB, 'z': ZiB, 'y': YiB, 'r': RiB, 'q': QiB.
+//
+// Values less than 1PiB (or 1PB) are formatted exactly as Bytes. The zero value is zero.
+type BigBytes struct {
+	i *big.Int
+}
+
+// NewBigBytes returns b as BigBytes.
+func NewBigBytes(b Bytes) BigBytes {
+	return BigBytes{i: new(big.Int).SetUint64(uint64(b))}
+}
+
+// BigBytesFromInt returns i as BigBytes, or ErrUnderflow if i is negative.
+func BigBytesFromInt(i *big.Int) (BigBytes, error) {
+	if i.Sign() < 0 {
+		return BigBytes{}, ErrUnderflow
+	}
+	return BigBytes{i: new(big.Int).Set(i)}, nil
+}
+
+// ParseBig parses a size like Parse does, with the units up to QiB and QB.
+func ParseBig(s string) (BigBytes, error) {
+	i, err := parseInt(s, func(unit string) (*big.Int, bool) {
+		mag, ok := bigParseUnits[unit]
+		return mag, ok
+	})
+	if err != nil {
+		return BigBytes{}, err
+	}
+	return BigBytes{i: i}, nil
+}
+
+func (b BigBytes) int() *big.Int {
+	if b.i == nil {
+		return new(big.Int)
+	}
+	return b.i
+}
+
+// Int returns b as a new big.Int.
+func (b BigBytes) Int() *big.Int {
+	return new(big.Int).Set(b.int())
+}
+
+// Bytes returns b as Bytes, or ErrOverflow if it exceeds the maximum of Bytes.
+func (b BigBytes) Bytes() (Bytes, error) {
+	if !b.int().IsUint64() {
+		return 0, ErrOverflow
+	}
+	return Bytes(b.int().Uint64()), nil
+}
+
+// Cmp compares b and o, and returns -1, 0 or +1 like big.Int.Cmp.
+func (b BigBytes) Cmp(o BigBytes) int {
+	return b.int().Cmp(o.int())
+}
+
+// Add returns b+o.
+func (b BigBytes) Add(o BigBytes) BigBytes {
+	return BigBytes{i: new(big.Int).Add(b.int(), o.int())}
+}
+
+// Sub returns b-o, or ErrUnderflow if o is greater than b.
+func (b BigBytes) Sub(o BigBytes) (BigBytes, error) {
+	if b.Cmp(o) < 0 {
+		return BigBytes{}, ErrUnderflow
+	}
+	return BigBytes{i: new(big.Int).Sub(b.int(), o.int())}, nil
+}
+
+// Mul returns b multiplied by n.
+func (b BigBytes) Mul(n uint64) BigBytes {
+	return BigBytes{i: new(big.Int).Mul(b.int(), new(big.Int).SetUint64(n))}
+}
+
+// Format implements fmt.Formatter, see the type document for the verbs.
+func (b BigBytes) Format(f fmt.State, verb rune) {
+	units := bigBinaryUnits
+	if f.Flag('#') {
+		units = bigDecimalUnits
+	}
+	v := b.int()
+
+	var unit bigUnit
+	switch verb {
+	case 's', 'v', 'f':
+		unit = units[0]
+		for _, u := range units {
+			if v.Cmp(u.mag) >= 0 {
+				unit = u
+			}
+		}
+	case 'b', 'd':
+		unit = units[0]
+	default:
+		for _, u := range units {
+			if u.verb == verb {
+				unit = u
+			}
+		}
+		if unit.mag == nil {
+			fmt.Fprintf(f, "%%!%c(units.BigBytes=%s)", verb, v)
+			return
+		}
+	}
+	// share the formatting of Bytes as long as it can
+	if v.IsUint64() && unit.mag.Cmp(units[len(binaryMagnitudes)].mag) < 0 {
+		Bytes(v.Uint64()).Format(f, verb)
+		return
+	}
+
+	width, hasWidth := f.Width()
+	if verb == 'd' {
+		fmt.Fprintf(f, "%*s", width, v)
+		return
+	}
+	if hasWidth && !f.Flag(' ') {
+		width -= len(unit.name)
+	}
+	if verb == 'f' {
+		precision := 1
+		if p, ok := f.Precision(); ok {
+			precision = p
+		}
+		q := new(big.Float).SetPrec(256).SetInt(v)
+		q.Quo(q, new(big.Float).SetPrec(256).SetInt(unit.mag))
+		fmt.Fprintf(f, "%*s", width, q.Text('f', precision))
+	} else {
+		fmt.Fprintf(f, "%*s", width, new(big.Int).Quo(v, unit.mag))
+	}
+	if !f.Flag(' ') {
+		f.Write([]byte(unit.name))
+	}
+}
@@ -0,0 +1,107 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigBytes_Format(t *testing.T) {
+	// 2^64 sectors of 512 bytes
+	sectors := NewBigBytes(math.MaxUint64).Add(NewBigBytes(1)).Mul(512)
+	yib, err := ParseBig("3.5YiB")
+	require.NoError(t, err)
+
+	tests := []struct {
+		format string
+		b      BigBytes
+		want   string
+	}{
+		{format: "%f", b: sectors, want: "8.0ZiB"},
+		{format: "%#.2f", b: sectors, want: "9.44ZB"},
+		{format: "%s", b: sectors, want: "8ZiB"},
+		{format: "%d", b: sectors, want: "9444732965739290427392"},
+		{format: "%b", b: sectors, want: "9444732965739290427392B"},
+		{format: "%e", b: sectors, want: "8192EiB"},
+		{format: "%t", b: sectors, want: "8589934592TiB"},
+		{format: "%f", b: yib, want: "3.5YiB"},
+		{format: "%10.2f", b: yib, want: "   3.50YiB"},
+		{format: "% 10.2f", b: yib, want: "      3.50"},
+		{format: "%q", b: yib.Mul(1024 * 1024), want: "3QiB"},
+		{format: "%f", b: yib.Mul(1 << 30), want: "3584.0QiB"},
+		{format: "%f", b: NewBigBytes(1 << 50), want: "1.0PiB"},
+		{format: "%#s", b: NewBigBytes(1000 * TB), want: "1PB"},
+		{format: "%f", b: NewBigBytes(KiB + 512), want: "1.5kiB"},
+		{format: "%f", b: BigBytes{}, want: "0.0B"},
+		{format: "%x", b: NewBigBytes(1), want: "%!x(units.BigBytes=1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, fmt.Sprintf(tt.format, tt.b))
+		})
+	}
+}
+
+func TestBigBytes_Format_consistent(t *testing.T) {
+	for _, b := range []Bytes{0, 1023, KiB + 1, 3*MiB + 7, 5 * GiB, 7*TiB + 1, 900 * TiB, 999 * TB} {
+		for _, format := range []string{"%f", "%#.3f", "%s", "%#v", "%k", "%#g", "%10.2f", "% 7s", "%d", "%b"} {
+			assert.Equal(t, fmt.Sprintf(format, b), fmt.Sprintf(format, NewBigBytes(b)), "%s of %d", format, b)
+		}
+	}
+}
+
+func TestParseBig(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    string
+		wantErr error
+	}{
+		{s: "1KiB", want: "1024"},
+		{s: "1.5 PiB", want: "1688849860263936"},
+		{s: "1EB", want: "1000000000000000000"},
+		{s: "1Z", want: "1180591620717411303424"},
+		{s: "2yb", want: "2000000000000000000000000"},
+		{s: "1RiB", want: "1237940039285380274899124224"},
+		{s: "1QB", want: "1000000000000000000000000000000"},
+		{s: "1XiB", wantErr: ErrSyntax},
+		{s: "-1", wantErr: ErrSyntax},
+	}
+	for _, tt := range tests {
+		got, err := ParseBig(tt.s)
+		if tt.wantErr != nil {
+			assert.True(t, errors.Is(err, tt.wantErr), tt.s)
+			continue
+		}
+		assert.NoError(t, err, tt.s)
+		assert.Equal(t, tt.want, got.Int().String(), tt.s)
+	}
+}
+
+func TestBigBytes_conversion(t *testing.T) {
+	b, err := NewBigBytes(GiB).Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, GiB, b)
+
+	_, err = NewBigBytes(math.MaxUint64).Add(NewBigBytes(1)).Bytes()
+	assert.Equal(t, ErrOverflow, err)
+
+	_, err = BigBytesFromInt(big.NewInt(-1))
+	assert.Equal(t, ErrUnderflow, err)
+	i := big.NewInt(42)
+	bb, err := BigBytesFromInt(i)
+	assert.NoError(t, err)
+	i.SetInt64(0)
+	assert.Equal(t, "42", bb.Int().String())
+
+	_, err = NewBigBytes(1).Sub(NewBigBytes(2))
+	assert.Equal(t, ErrUnderflow, err)
+	d, err := NewBigBytes(2).Sub(NewBigBytes(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, d.Cmp(NewBigBytes(1)))
+	assert.Equal(t, -1, BigBytes{}.Cmp(d))
+}
@@ -0,0 +1,59 @@
+package units
+
+import "fmt"
+
+// Bounded formats Bytes like Bytes does, except that the unit chosen by verbs 's', 'v' and 'f'
+// is kept between Min and Max, e.g. never shows bytes or never goes beyond GiB.
+//
+// Min and Max are units, KiB and KB are the same bound as the '#' flag picks the unit system.
+// A zero Min means B and a zero Max means TiB. If Min is greater than Max, they are swapped.
+type Bounded struct {
+	Bytes    Bytes
+	Min, Max Bytes
+}
+
+// Bounded returns b to be formatted with units between min and max.
+func (b Bytes) Bounded(min, max Bytes) Bounded {
+	return Bounded{Bytes: b, Min: min, Max: max}
+}
+
+// Format implements fmt.Formatter.
+func (b Bounded) Format(f fmt.State, verb rune) {
+	minLevel, maxLevel := 0, len(binaryMagnitudes)-1
+	if b.Min != 0 {
+		minLevel = unitLevel(b.Min)
+	}
+	if b.Max != 0 {
+		maxLevel = unitLevel(b.Max)
+	}
+	if minLevel > maxLevel {
+		minLevel, maxLevel = maxLevel, minLevel
+	}
+	b.Bytes.format(f, verb, minLevel, maxLevel)
+}
+
+// unitLevel returns the index of unit u in the magnitude tables,
+// a value which is not a unit is taken as its binary order of magnitude.
+func unitLevel(u Bytes) int {
+	for _, mags := range [][]Bytes{binaryMagnitudes, decimalMagnitudes} {
+		for i, mag := range mags {
+			if mag == u {
+				return i
+			}
+		}
+	}
+	return unitLevel(u.magnitude())
+}
+
+// clampMagnitude keeps mag in range [minLevel, maxLevel] of the binary or decimal magnitude table.
+func clampMagnitude(mag Bytes, decimal bool, minLevel, maxLevel int) Bytes {
+	mags := magnitudes[decimal]
+	level := unitLevel(mag)
+	if level < minLevel {
+		level = minLevel
+	}
+	if level > maxLevel {
+		level = maxLevel
+	}
+	return mags[level]
+}
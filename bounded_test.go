@@ -0,0 +1,48 @@
+package units
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBounded_Format(t *testing.T) {
+	tests := []struct {
+		format   string
+		b        Bytes
+		min, max Bytes
+		want     string
+	}{
+		{format: "%f", b: 12, min: KiB, want: "0.0kiB"},
+		{format: "%.2f", b: 12, min: KiB, want: "0.01kiB"},
+		{format: "%s", b: 12, min: KiB, want: "0kiB"},
+		{format: "%#f", b: 12, min: KiB, want: "0.0kB"},
+		{format: "%f", b: 3 * TiB, max: GiB, want: "3072.0GiB"},
+		{format: "%#s", b: 3 * TB, max: GB, want: "3000GB"},
+		{format: "%v", b: 5 * MiB, min: KiB, max: GiB, want: "5MiB"},
+		{format: "%f", b: 512, min: MiB, max: MiB, want: "0.0MiB"},
+		{format: "%f", b: 512, min: KB, want: "0.5kiB"},
+		{format: "%8.2f", b: 12, min: KiB, want: " 0.01kiB"},
+		{format: "% f", b: 12, min: KiB, want: "0.0"},
+		{format: "%k", b: 3 * MiB, max: KiB, want: "3072kiB"},
+		{format: "%d", b: 3 * MiB, min: GiB, want: "3145728"},
+		{format: "%f", b: 12, want: "12.0B"},
+		{format: "%f", b: 5 * MiB, min: GiB, max: KiB, want: "5.0MiB"},
+		{format: "%f", b: 12, min: GiB, max: KiB, want: "0.0kiB"},
+		{format: "%f", b: 3 * TiB, min: GiB, max: KiB, want: "3072.0GiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, fmt.Sprintf(tt.format, tt.b.Bounded(tt.min, tt.max)))
+		})
+	}
+}
+
+func Test_unitLevel(t *testing.T) {
+	assert.Equal(t, 0, unitLevel(B))
+	assert.Equal(t, 1, unitLevel(KiB))
+	assert.Equal(t, 1, unitLevel(KB))
+	assert.Equal(t, 4, unitLevel(TB))
+	assert.Equal(t, 2, unitLevel(4*MiB))
+}
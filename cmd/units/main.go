@@ -0,0 +1,187 @@
+// Command units parses, rounds and formats sizes with the same semantics as package units.
+//
+// Usage:
+//
+//	units [flags] [size ...]
+//
+// Sizes are read from the arguments, or from the standard input line by line if there is no argument.
+//
+// With -numfmt, it filters the standard input like GNU numfmt, converting byte counts
+// in the fields selected by -field or -match and passing other text through.
+//
+// Examples:
+//
+//	units 3.5GiB                  # 3.5GiB
+//	units -format %d 3.5GiB       # 3758096384
+//	units -format '%#.2f' 3.5GiB  # 3.76GB
+//	units -round ceil 1500MiB     # 2.0GiB
+//	units -round round -by 4KiB 6KiB  # 8.0kiB
+//	ls -l | units -numfmt -header 1 -field 5
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ylin610/units"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type options struct {
+	format  string
+	round   string
+	decimal bool
+	by      units.Bytes
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("units", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var opts options
+	var by string
+	fs.StringVar(&opts.format, "format", "%f", "`format` of the output, see package units for the verbs and flags")
+	fs.StringVar(&opts.round, "round", "", "round the size, one of ceil, floor and round")
+	fs.BoolVar(&opts.decimal, "decimal", false, "round by the decimal order of magnitude instead of the binary one, requires -round")
+	fs.StringVar(&by, "by", "", "round by the `size` instead of the order of magnitude, requires -round")
+	var numfmt units.Numfmt
+	var numfmtMode bool
+	var fields, match string
+	fs.BoolVar(&numfmtMode, "numfmt", false, "filter the standard input, converting byte counts in the selected fields, without rounding")
+	fs.StringVar(&fields, "field", "", "comma separated 1-based `indexes` of the fields to convert in -numfmt mode, default is 1")
+	fs.StringVar(&match, "match", "", "convert the fields matching the `regexp` in -numfmt mode")
+	fs.StringVar(&numfmt.Delimiter, "delimiter", "", "field `delimiter` in -numfmt mode, default is blanks")
+	fs.IntVar(&numfmt.Header, "header", 0, "number of header `lines` passed through in -numfmt mode")
+	fs.BoolVar(&numfmt.Reverse, "from", false, "convert human-readable sizes to byte counts in -numfmt mode")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: units [flags] [size ...]\n\nAccepted units: %s\n\nFlags:\n", units.AcceptedUnits)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	switch opts.round {
+	case "", "ceil", "floor", "round":
+	default:
+		fmt.Fprintf(stderr, "units: invalid -round %q\n", opts.round)
+		return 2
+	}
+	if by != "" {
+		b, err := units.Parse(by)
+		if err != nil || b == 0 {
+			fmt.Fprintf(stderr, "units: invalid -by %q\n", by)
+			return 2
+		}
+		opts.by = b
+	}
+	if opts.round == "" && (opts.by != 0 || opts.decimal) {
+		fmt.Fprintln(stderr, "units: -by and -decimal require -round")
+		return 2
+	}
+	if opts.by != 0 && opts.decimal {
+		fmt.Fprintln(stderr, "units: -by and -decimal cannot be used together")
+		return 2
+	}
+
+	if numfmtMode && opts.round != "" {
+		fmt.Fprintln(stderr, "units: -round, -by and -decimal cannot be used with -numfmt")
+		return 2
+	}
+
+	if numfmtMode {
+		for _, field := range strings.Split(fields, ",") {
+			if field == "" {
+				continue
+			}
+			i, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || i < 1 {
+				fmt.Fprintf(stderr, "units: invalid -field %q\n", fields)
+				return 2
+			}
+			numfmt.Fields = append(numfmt.Fields, i)
+		}
+		if match != "" {
+			re, err := regexp.Compile(match)
+			if err != nil {
+				fmt.Fprintf(stderr, "units: invalid -match: %v\n", err)
+				return 2
+			}
+			numfmt.Match = re
+		}
+		numfmt.Format = opts.format
+		if err := numfmt.Filter(stdin, stdout); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	code := 0
+	convert := func(s string) {
+		out, err := opts.convert(s)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = 1
+			return
+		}
+		fmt.Fprintln(stdout, out)
+	}
+	if fs.NArg() > 0 {
+		for _, arg := range fs.Args() {
+			convert(arg)
+		}
+		return code
+	}
+
+	// not bufio.Scanner, which fails on long lines
+	br := bufio.NewReader(stdin)
+	for {
+		line, err := br.ReadString('\n')
+		if line = strings.TrimSpace(line); line != "" {
+			convert(line)
+		}
+		if err == io.EOF {
+			return code
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+}
+
+func (o *options) convert(s string) (string, error) {
+	b, err := units.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if o.round == "" {
+		return fmt.Sprintf(o.format, b), nil
+	}
+	// the rounding methods wrap around on overflow, which the floor never does
+	var ceil, floor, round func() units.Bytes
+	switch {
+	case o.by != 0:
+		ceil = func() units.Bytes { return b.CeilBy(o.by) }
+		floor = func() units.Bytes { return b.FloorBy(o.by) }
+		round = func() units.Bytes { return b.RoundBy(o.by) }
+	case o.decimal:
+		ceil, floor, round = b.DecimalCeil, b.DecimalFloor, b.DecimalRound
+	default:
+		ceil, floor, round = b.Ceil, b.Floor, b.Round
+	}
+	rounded := map[string]func() units.Bytes{"ceil": ceil, "floor": floor, "round": round}[o.round]()
+	if rounded < floor() {
+		return "", fmt.Errorf("units: %s of %q overflows", o.round, s)
+	}
+	b = rounded
+	return fmt.Sprintf(o.format, b), nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantOut    string
+		wantErr    string
+		wantStatus int
+	}{
+		{name: "default", args: []string{"3.5GiB", "1536"}, wantOut: "3.5GiB\n1.5kiB\n"},
+		{name: "bytes", args: []string{"-format", "%d", "3.5GiB"}, wantOut: "3758096384\n"},
+		{name: "decimal", args: []string{"-format", "%#.2f", "3.5GiB"}, wantOut: "3.76GB\n"},
+		{name: "verb", args: []string{"-format", "%m", "3.5GiB"}, wantOut: "3584MiB\n"},
+		{name: "ceil", args: []string{"-round", "ceil", "1500MiB"}, wantOut: "2.0GiB\n"},
+		{name: "decimal floor", args: []string{"-round", "floor", "-decimal", "-format", "%#f", "1500MB"}, wantOut: "1.0GB\n"},
+		{name: "round by", args: []string{"-round", "round", "-by", "4KiB", "6KiB"}, wantOut: "8.0kiB\n"},
+		{name: "stdin", stdin: "1K\n\n2 MB\n", wantOut: "1.0kiB\n1.9MiB\n"},
+		{name: "stdin crlf", stdin: "1K\r\n2 MB", wantOut: "1.0kiB\n1.9MiB\n"},
+		{
+			name:       "stdin long line",
+			stdin:      strings.Repeat("1", 100*1024) + "\n1K\n",
+			wantOut:    "1.0kiB\n",
+			wantErr:    "units: parsing \"" + strings.Repeat("1", 100*1024) + "\": overflow\n",
+			wantStatus: 1,
+		},
+		{
+			name:    "numfmt long line",
+			args:    []string{"-numfmt"},
+			stdin:   "2048 " + strings.Repeat("x", 100*1024) + "\n",
+			wantOut: "2.0kiB " + strings.Repeat("x", 100*1024) + "\n",
+		},
+		{
+			name:       "invalid size",
+			args:       []string{"1PiB", "1K"},
+			wantOut:    "1.0kiB\n",
+			wantErr:    "units: parsing \"1PiB\": invalid syntax\n",
+			wantStatus: 1,
+		},
+		{
+			name:       "ceil overflow",
+			args:       []string{"-round", "ceil", "18446744073709551615", "1K"},
+			wantOut:    "1.0kiB\n",
+			wantErr:    "units: ceil of \"18446744073709551615\" overflows\n",
+			wantStatus: 1,
+		},
+		{
+			name:       "round by overflow",
+			args:       []string{"-round", "round", "-by", "10", "18446744073709551615"},
+			wantErr:    "units: round of \"18446744073709551615\" overflows\n",
+			wantStatus: 1,
+		},
+		{name: "floor max", args: []string{"-round", "floor", "-format", "%d", "18446744073709551615"}, wantOut: "18446742974197923840\n"},
+		{name: "round by max", args: []string{"-round", "round", "-by", "3", "-format", "%d", "18446744073709551615"}, wantOut: "18446744073709551615\n"},
+		{name: "invalid round", args: []string{"-round", "up", "1K"}, wantErr: "units: invalid -round \"up\"\n", wantStatus: 2},
+		{
+			name:    "numfmt",
+			args:    []string{"-numfmt", "-header", "1", "-field", "2,3"},
+			stdin:   "NAME SIZE USED\na    2048 1536\n",
+			wantOut: "NAME SIZE USED\na  2.0kiB 1.5kiB\n",
+		},
+		{
+			name:    "numfmt match",
+			args:    []string{"-numfmt", "-match", "^[0-9]+$", "-format", "%#s", "-delimiter", ","},
+			stdin:   "a,2000,x\n",
+			wantOut: "a,2kB,x\n",
+		},
+		{
+			name:    "numfmt from",
+			args:    []string{"-numfmt", "-from"},
+			stdin:   "1kiB a\n",
+			wantOut: "1024 a\n",
+		},
+		{
+			name:       "numfmt round",
+			args:       []string{"-numfmt", "-round", "ceil", "-by", "4KiB"},
+			stdin:      "5000\n",
+			wantErr:    "units: -round, -by and -decimal cannot be used with -numfmt\n",
+			wantStatus: 2,
+		},
+		{
+			name:       "numfmt decimal",
+			args:       []string{"-numfmt", "-decimal"},
+			stdin:      "5000\n",
+			wantErr:    "units: -by and -decimal require -round\n",
+			wantStatus: 2,
+		},
+		{name: "invalid field", args: []string{"-numfmt", "-field", "0"}, wantErr: "units: invalid -field \"0\"\n", wantStatus: 2},
+		{name: "invalid by", args: []string{"-by", "0", "1K"}, wantErr: "units: invalid -by \"0\"\n", wantStatus: 2},
+		{name: "by without round", args: []string{"-by", "4KiB", "6KiB"}, wantErr: "units: -by and -decimal require -round\n", wantStatus: 2},
+		{name: "decimal without round", args: []string{"-decimal", "1500MB"}, wantErr: "units: -by and -decimal require -round\n", wantStatus: 2},
+		{
+			name:       "by and decimal",
+			args:       []string{"-round", "ceil", "-by", "4KiB", "-decimal", "6KiB"},
+			wantErr:    "units: -by and -decimal cannot be used together\n",
+			wantStatus: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantOut, stdout.String())
+			assert.Equal(t, tt.wantErr, stderr.String())
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package units
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UnitPolicy decides which value picks the common unit of a set of values.
+type UnitPolicy int
+
+const (
+	// UnitByMax picks the unit of the maximum, so that no value has more integer digits than needed.
+	UnitByMax UnitPolicy = iota
+	// UnitByMedian picks the unit of the median, so that typical values read best.
+	UnitByMedian
+	// UnitByMin picks the unit of the minimum, so that no value is shown as zero unless it is.
+	UnitByMin
+)
+
+// CommonMagnitude returns the order of magnitude shared by bs, picked by policy.
+// Decimal magnitudes are used if decimal is true. It returns B if bs is empty.
+func CommonMagnitude(bs []Bytes, decimal bool, policy UnitPolicy) Bytes {
+	if len(bs) == 0 {
+		return B
+	}
+	sorted := append([]Bytes(nil), bs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var b Bytes
+	switch policy {
+	case UnitByMedian:
+		b = sorted[(len(sorted)-1)/2]
+	case UnitByMin:
+		b = sorted[0]
+	default:
+		b = sorted[len(sorted)-1]
+	}
+	if decimal {
+		return b.decimalMagnitude()
+	}
+	return b.magnitude()
+}
+
+// FormatCommon formats bs in their common unit picked by policy, with precision digits after the decimal point,
+// zero means integers.
+func FormatCommon(bs []Bytes, decimal bool, policy UnitPolicy, precision int) []string {
+	mag := CommonMagnitude(bs, decimal, policy)
+	result := make([]string, len(bs))
+	for i, b := range bs {
+		number, unit := b.formatIn(mag, precision)
+		result[i] = number + unit
+	}
+	return result
+}
+
+// formatIn formats b in the unit mag like verb 'f' does, returning the number and the unit separately.
+// A negative precision is taken as zero.
+func (b Bytes) formatIn(mag Bytes, precision int) (number, unit string) {
+	if precision < 0 {
+		precision = 0
+	}
+	return fmt.Sprintf("%.*f", precision, float64(b)/float64(mag)), string(unitNames[mag])
+}
@@ -0,0 +1,40 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommonMagnitude(t *testing.T) {
+	bs := []Bytes{900 * MiB, 2 * GiB, 12 * KiB, 300 * MiB}
+	tests := []struct {
+		name    string
+		bs      []Bytes
+		decimal bool
+		policy  UnitPolicy
+		want    Bytes
+	}{
+		{name: "max", bs: bs, policy: UnitByMax, want: GiB},
+		{name: "median", bs: bs, policy: UnitByMedian, want: MiB},
+		{name: "min", bs: bs, policy: UnitByMin, want: KiB},
+		{name: "decimal max", bs: bs, decimal: true, policy: UnitByMax, want: GB},
+		{name: "decimal min", bs: bs, decimal: true, policy: UnitByMin, want: KB},
+		{name: "empty", bs: nil, policy: UnitByMedian, want: B},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CommonMagnitude(tt.bs, tt.decimal, tt.policy))
+		})
+	}
+	// the input is not reordered
+	assert.Equal(t, 900*MiB, bs[0])
+}
+
+func TestFormatCommon(t *testing.T) {
+	bs := []Bytes{900 * MiB, GiB + 512*MiB, 0}
+	assert.Equal(t, []string{"0.88GiB", "1.50GiB", "0.00GiB"}, FormatCommon(bs, false, UnitByMax, 2))
+	assert.Equal(t, []string{"900MiB", "1536MiB", "0MiB"}, FormatCommon(bs, false, UnitByMedian, 0))
+	assert.Equal(t, FormatCommon(bs, false, UnitByMedian, 0), FormatCommon(bs, false, UnitByMedian, -1))
+	assert.Equal(t, []string{"0.9GB", "1.6GB", "0.0GB"}, FormatCommon(bs, true, UnitByMax, 1))
+}
@@ -0,0 +1,91 @@
+package units
+
+import "fmt"
+
+// Compression is the logical and physical size of data after compression or deduplication,
+// formatted like "10.0GiB -> 2.5GiB (4.00x, saved 7.5GiB)".
+type Compression struct {
+	// Logical is the size before compression.
+	Logical Bytes
+	// Physical is the size actually stored.
+	Physical Bytes
+}
+
+// SumCompression aggregates entries, or returns ErrOverflow if either total exceeds the maximum of Bytes.
+func SumCompression(entries []Compression) (Compression, error) {
+	var sum Compression
+	for _, c := range entries {
+		var err error
+		if sum, err = sum.Add(c); err != nil {
+			return Compression{}, err
+		}
+	}
+	return sum, nil
+}
+
+// Add returns the aggregation of c and o, or ErrOverflow if either total exceeds the maximum of Bytes.
+func (c Compression) Add(o Compression) (Compression, error) {
+	logical, err := Sum([]Bytes{c.Logical, o.Logical})
+	if err != nil {
+		return Compression{}, err
+	}
+	physical, err := Sum([]Bytes{c.Physical, o.Physical})
+	if err != nil {
+		return Compression{}, err
+	}
+	return Compression{Logical: logical, Physical: physical}, nil
+}
+
+// Ratio returns the compression ratio Logical/Physical. It is less than 1 if the data grew,
+// +Inf if only Physical is zero, and NaN if both are zero.
+func (c Compression) Ratio() float64 {
+	return Ratio(c.Logical, c.Physical)
+}
+
+// Saved returns the space saved, zero if the data grew.
+func (c Compression) Saved() Bytes {
+	if c.Physical >= c.Logical {
+		return 0
+	}
+	return c.Logical - c.Physical
+}
+
+// Grown returns the space lost if the data grew, zero otherwise.
+func (c Compression) Grown() Bytes {
+	if c.Logical >= c.Physical {
+		return 0
+	}
+	return c.Physical - c.Logical
+}
+
+// SavedPercent returns the space saved in percentage of Logical, negative if the data grew.
+func (c Compression) SavedPercent() float64 {
+	return (float64(c.Logical) - float64(c.Physical)) / float64(c.Logical) * 100
+}
+
+func (c Compression) String() string {
+	return fmt.Sprintf("%v", c)
+}
+
+// Format implements fmt.Formatter. The verb and flags apply to all sizes, e.g. "%#.2f",
+// verb 'v' and 's' are formatted like 'f'. The ratio is omitted if Physical is zero.
+func (c Compression) Format(f fmt.State, verb rune) {
+	if verb == 'v' || verb == 's' {
+		verb = 'f'
+	}
+	c.Logical.Format(f, verb)
+	f.Write([]byte(" -> "))
+	c.Physical.Format(f, verb)
+	f.Write([]byte(" ("))
+	if c.Physical != 0 {
+		fmt.Fprintf(f, "%.2fx, ", c.Ratio())
+	}
+	if grown := c.Grown(); grown != 0 {
+		f.Write([]byte("grew "))
+		grown.Format(f, verb)
+	} else {
+		f.Write([]byte("saved "))
+		c.Saved().Format(f, verb)
+	}
+	f.Write([]byte(")"))
+}
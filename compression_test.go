@@ -0,0 +1,65 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	c := Compression{Logical: 10 * GiB, Physical: 2*GiB + 512*MiB}
+	assert.Equal(t, 4.0, c.Ratio())
+	assert.Equal(t, 7*GiB+512*MiB, c.Saved())
+	assert.Equal(t, Bytes(0), c.Grown())
+	assert.Equal(t, 75.0, c.SavedPercent())
+
+	grown := Compression{Logical: 4 * KiB, Physical: 5 * KiB}
+	assert.Equal(t, 0.8, grown.Ratio())
+	assert.Equal(t, Bytes(0), grown.Saved())
+	assert.Equal(t, KiB, grown.Grown())
+	assert.Equal(t, -25.0, grown.SavedPercent())
+
+	assert.True(t, math.IsInf(Compression{Logical: KiB}.Ratio(), 1))
+	assert.True(t, math.IsNaN(Compression{}.Ratio()))
+}
+
+func TestCompression_Format(t *testing.T) {
+	tests := []struct {
+		format string
+		c      Compression
+		want   string
+	}{
+		{"%v", Compression{Logical: 10 * GiB, Physical: 2*GiB + 512*MiB}, "10.0GiB -> 2.5GiB (4.00x, saved 7.5GiB)"},
+		{"%s", Compression{Logical: 10 * GiB, Physical: 2*GiB + 512*MiB}, "10.0GiB -> 2.5GiB (4.00x, saved 7.5GiB)"},
+		{"%#.2f", Compression{Logical: 10 * GB, Physical: 3 * GB}, "10.00GB -> 3.00GB (3.33x, saved 7.00GB)"},
+		{"%m", Compression{Logical: 10 * GiB, Physical: 4 * GiB}, "10240MiB -> 4096MiB (2.50x, saved 6144MiB)"},
+		{"%v", Compression{Logical: 4 * KiB, Physical: 5 * KiB}, "4.0kiB -> 5.0kiB (0.80x, grew 1.0kiB)"},
+		{"%v", Compression{Logical: KiB}, "1.0kiB -> 0.0B (saved 1.0kiB)"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fmt.Sprintf(tt.format, tt.c), tt.format)
+	}
+	c := Compression{Logical: 2 * MiB, Physical: MiB}
+	assert.Equal(t, "2.0MiB -> 1.0MiB (2.00x, saved 1.0MiB)", c.String())
+}
+
+func TestSumCompression(t *testing.T) {
+	sum, err := SumCompression([]Compression{
+		{Logical: 6 * GiB, Physical: GiB},
+		{Logical: 4 * GiB, Physical: GiB + 512*MiB},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Compression{Logical: 10 * GiB, Physical: 2*GiB + 512*MiB}, sum)
+
+	sum, err = SumCompression(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Compression{}, sum)
+
+	_, err = SumCompression([]Compression{{Logical: math.MaxUint64}, {Logical: 1}})
+	assert.Equal(t, ErrOverflow, err)
+	_, err = SumCompression([]Compression{{Physical: math.MaxUint64}, {Physical: 1}})
+	assert.Equal(t, ErrOverflow, err)
+}
@@ -0,0 +1,240 @@
+package units
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DirUsage is the disk usage of a directory tree.
+type DirUsage struct {
+	// Apparent is the sum of the sizes of the files and the directories themselves, like "du --apparent-size".
+	Apparent Bytes
+	// Allocated is the sum of the blocks allocated to the files and the directories themselves.
+	// It equals to Apparent if the file system does not report the blocks.
+	Allocated Bytes
+	// Files is the number of non-directory entries.
+	Files int
+	// Dirs is the number of directories, including the directory itself.
+	Dirs int
+}
+
+func (u *DirUsage) add(o DirUsage) {
+	u.Apparent += o.Apparent
+	u.Allocated += o.Allocated
+	u.Files += o.Files
+	u.Dirs += o.Dirs
+}
+
+// DirSizeOptions configures DirSize.
+type DirSizeOptions struct {
+	// Workers is the maximum number of directories read concurrently, default is runtime.NumCPU().
+	Workers int
+	// OnError is called for every path which cannot be read, the walk skips it and goes on.
+	// It may be called concurrently. If it is nil, the errors are returned as PathErrors.
+	OnError func(err *fs.PathError)
+}
+
+// PathErrors is the paths DirSize could not read, the usages exclude them.
+type PathErrors []*fs.PathError
+
+func (e PathErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e[0].Error(), len(e)-1)
+}
+
+// Is reports whether any of the errors matches target.
+func (e PathErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// DirSize walks the directory tree root in fsys like du does, and returns the usage of every directory
+// keyed by its path, the usage of a directory includes all its descendants.
+//
+// Symbolic links are not followed, and files with several hard links are only counted once
+// if the file system exposes the inode numbers, like os.DirFS does on Linux.
+//
+// Like du, paths which cannot be read are reported and skipped. Unless OnError is set, they are returned
+// as PathErrors along with the usages of everything else. It stops early only if ctx is done,
+// or if root itself cannot be read.
+func DirSize(ctx context.Context, fsys fs.FS, root string, opts *DirSizeOptions) (map[string]DirUsage, error) {
+	workers := runtime.NumCPU()
+	var onError func(*fs.PathError)
+	if opts != nil {
+		if opts.Workers > 0 {
+			workers = opts.Workers
+		}
+		onError = opts.OnError
+	}
+
+	w := &dirWalker{
+		ctx:     ctx,
+		fsys:    fsys,
+		root:    root,
+		sem:     make(chan struct{}, workers-1),
+		onError: onError,
+		usages:  map[string]DirUsage{},
+		seen:    map[fileID]struct{}{},
+	}
+	w.walk(root)
+	w.wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if w.rootErr != nil {
+		return nil, w.rootErr
+	}
+
+	// roll up from the deepest directories, so that every directory includes its descendants
+	dirs := make([]string, 0, len(w.usages))
+	for dir := range w.usages {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], "/") > strings.Count(dirs[j], "/")
+	})
+	for _, dir := range dirs {
+		if dir == root {
+			continue
+		}
+		parent := w.usages[path.Dir(dir)]
+		parent.add(w.usages[dir])
+		w.usages[path.Dir(dir)] = parent
+	}
+	if len(w.errs) > 0 {
+		sort.Slice(w.errs, func(i, j int) bool { return w.errs[i].Path < w.errs[j].Path })
+		return w.usages, w.errs
+	}
+	return w.usages, nil
+}
+
+// fileID identifies a file on a device.
+type fileID struct {
+	dev, ino uint64
+}
+
+type dirWalker struct {
+	ctx  context.Context
+	fsys fs.FS
+	root string
+	// rootErr is the error of reading root, which fails the walk
+	rootErr error
+	// sem limits the extra goroutines, the calling goroutine is a worker too
+	sem     chan struct{}
+	wg      sync.WaitGroup
+	onError func(*fs.PathError)
+
+	mu     sync.Mutex
+	errs   PathErrors
+	usages map[string]DirUsage
+	seen   map[fileID]struct{}
+}
+
+// report records the error of reading name, which is skipped.
+func (w *dirWalker) report(op, name string, err error) {
+	var perr *fs.PathError
+	if !errors.As(err, &perr) {
+		perr = &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if w.onError != nil {
+		w.onError(perr)
+		return
+	}
+	w.mu.Lock()
+	w.errs = append(w.errs, perr)
+	w.mu.Unlock()
+}
+
+func (w *dirWalker) walk(dir string) {
+	if w.ctx.Err() != nil {
+		return
+	}
+	entries, err := fs.ReadDir(w.fsys, dir)
+	if err != nil && dir == w.root {
+		w.rootErr = err
+		return
+	}
+	if err != nil {
+		w.report("readdir", dir, err)
+		return
+	}
+
+	usage := DirUsage{Dirs: 1}
+	// count the blocks of the directory itself like du does
+	if info, err := fs.Stat(w.fsys, dir); err != nil {
+		w.report("stat", dir, err)
+	} else {
+		usage.Apparent, usage.Allocated = fileSize(info)
+	}
+	for _, entry := range entries {
+		name := path.Join(dir, entry.Name())
+		if entry.IsDir() {
+			select {
+			case w.sem <- struct{}{}:
+				w.wg.Add(1)
+				go func() {
+					defer func() {
+						<-w.sem
+						w.wg.Done()
+					}()
+					w.walk(name)
+				}()
+			default:
+				// no idle worker, walk in the current goroutine
+				w.walk(name)
+			}
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			w.report("stat", name, err)
+			continue
+		}
+		if _, id, _ := fileStat(info); id != nil && w.seenBefore(*id) {
+			continue
+		}
+		apparent, allocated := fileSize(info)
+		usage.Apparent += apparent
+		usage.Allocated += allocated
+		usage.Files++
+	}
+
+	w.mu.Lock()
+	w.usages[dir] = usage
+	w.mu.Unlock()
+}
+
+// fileSize returns the apparent and the allocated size of a file.
+func fileSize(info fs.FileInfo) (apparent, allocated Bytes) {
+	apparent = Bytes(info.Size())
+	allocated, _, ok := fileStat(info)
+	if !ok {
+		allocated = apparent
+	}
+	return apparent, allocated
+}
+
+// seenBefore records id and reports whether it has been recorded.
+func (w *dirWalker) seenBefore(id fileID) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.seen[id]; ok {
+		return true
+	}
+	w.seen[id] = struct{}{}
+	return false
+}
@@ -0,0 +1,23 @@
+//go:build linux
+// +build linux
+
+package units
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileStat returns the allocated size of the file, and its identity if it has several hard links.
+func fileStat(info fs.FileInfo) (allocated Bytes, id *fileID, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, nil, false
+	}
+	// st_blocks is always in 512-byte units regardless of the block size
+	allocated = Bytes(st.Blocks) * 512
+	if st.Nlink > 1 {
+		id = &fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}
+	}
+	return allocated, id, true
+}
@@ -0,0 +1,39 @@
+//go:build linux
+// +build linux
+
+package units
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirSize_hardLink(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a"), make([]byte, 5000), 0o644))
+	require.NoError(t, os.Link(filepath.Join(dir, "a"), filepath.Join(dir, "sub", "b")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "c"), []byte("x"), 0o644))
+
+	usages, err := DirSize(context.Background(), os.DirFS(dir), ".", nil)
+	require.NoError(t, err)
+	root := usages["."]
+	// directories count like du does
+	var dirs Bytes
+	for _, name := range []string{dir, filepath.Join(dir, "sub")} {
+		info, err := os.Stat(name)
+		require.NoError(t, err)
+		dirs += Bytes(info.Size())
+	}
+	assert.Equal(t, 5001+dirs, root.Apparent)
+	assert.Equal(t, 2, root.Files)
+	assert.Equal(t, 2, root.Dirs)
+	// blocks are allocated in file system blocks, so there are at least 8kiB for the two files
+	assert.GreaterOrEqual(t, uint64(root.Allocated), uint64(8*KiB))
+	assert.Zero(t, root.Allocated%512)
+}
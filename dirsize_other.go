@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package units
+
+import "io/fs"
+
+// fileStat reports the file system does not expose blocks and inodes.
+func fileStat(info fs.FileInfo) (allocated Bytes, id *fileID, ok bool) {
+	return 0, nil, false
+}
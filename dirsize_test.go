@@ -0,0 +1,99 @@
+package units
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"a.txt":       {Data: make([]byte, 100)},
+		"sub/b.txt":   {Data: make([]byte, 1024)},
+		"sub/deep/c":  {Data: make([]byte, 2048)},
+		"sub/deep/d":  {Data: make([]byte, 1)},
+		"other/e.txt": {Data: make([]byte, 10)},
+		"other/empty": {Mode: fs.ModeDir},
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		usages, err := DirSize(context.Background(), testFS(), ".", &DirSizeOptions{Workers: workers})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]DirUsage{
+			".":           {Apparent: 3183, Allocated: 3183, Files: 5, Dirs: 5},
+			"sub":         {Apparent: 3073, Allocated: 3073, Files: 3, Dirs: 2},
+			"sub/deep":    {Apparent: 2049, Allocated: 2049, Files: 2, Dirs: 1},
+			"other":       {Apparent: 10, Allocated: 10, Files: 1, Dirs: 2},
+			"other/empty": {Dirs: 1},
+		}, usages)
+	}
+
+	usages, err := DirSize(context.Background(), testFS(), "sub", nil)
+	require.NoError(t, err)
+	assert.Len(t, usages, 2)
+	assert.Equal(t, 3*KiB+1, usages["sub"].Apparent)
+}
+
+func TestDirSize_error(t *testing.T) {
+	_, err := DirSize(context.Background(), testFS(), "missing", nil)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = DirSize(ctx, testFS(), ".", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// failingFS fails to read the directories in fail.
+type failingFS struct {
+	fstest.MapFS
+	fail map[string]error
+}
+
+func (f failingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if err, ok := f.fail[name]; ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func TestDirSize_partial(t *testing.T) {
+	fsys := failingFS{MapFS: testFS(), fail: map[string]error{
+		"sub/deep": fs.ErrPermission,
+		"other":    errors.New("broken"),
+	}}
+	usages, err := DirSize(context.Background(), fsys, ".", &DirSizeOptions{Workers: 2})
+	var errs PathErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 2)
+	assert.Equal(t, "other", errs[0].Path)
+	assert.Equal(t, "sub/deep", errs[1].Path)
+	assert.ErrorIs(t, err, fs.ErrPermission)
+	assert.EqualError(t, err, "open other: broken (and 1 more errors)")
+	assert.Equal(t, map[string]DirUsage{
+		".":   {Apparent: 1124, Allocated: 1124, Files: 2, Dirs: 2},
+		"sub": {Apparent: 1024, Allocated: 1024, Files: 1, Dirs: 1},
+	}, usages)
+
+	var mu sync.Mutex
+	var reported []string
+	usages, err = DirSize(context.Background(), fsys, ".", &DirSizeOptions{OnError: func(err *fs.PathError) {
+		mu.Lock()
+		reported = append(reported, err.Path)
+		mu.Unlock()
+	}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"other", "sub/deep"}, reported)
+	assert.Equal(t, Bytes(1124), usages["."].Apparent)
+
+	_, err = DirSize(context.Background(), fsys, "other", &DirSizeOptions{OnError: func(*fs.PathError) {}})
+	assert.EqualError(t, err, "open other: broken")
+}
@@ -59,6 +59,13 @@ The width includes the unit.
   fmt.Sprintf("%8.2f", units.KiB)  // " 1.00kiB"
   fmt.Sprintf("% 8.2f", units.KiB) // "    1.00"
 
+# Unit bounds
+
+[Bytes.Bounded] keeps the unit chosen by 's', 'v' and 'f' between a minimum and a maximum unit.
+
+  fmt.Sprintf("%f", units.Bytes(12).Bounded(units.KiB, 0)) // 0.0kiB
+  fmt.Sprintf("%f", (3*units.TiB).Bounded(0, units.GiB))   // 3072.0GiB
+
 [example_test.go]: https://github.com/ylin610/units/blob/main/example_test.go
 */
 package units
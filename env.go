@@ -0,0 +1,122 @@
+package units
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvError is returned when an environment variable is not a valid size.
+type EnvError struct {
+	// Name is the name of the variable.
+	Name string
+	// Value is the value of the variable.
+	Value string
+	// Err is the reason, ErrSyntax or ErrOverflow.
+	Err error
+}
+
+func (e *EnvError) Error() string {
+	return "units: parsing $" + e.Name + "=" + strconv.Quote(e.Value) + ": " + reason(e.Err) +
+		" (accepted units: " + AcceptedUnits + ")"
+}
+
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
+
+// Getenv parses the environment variable name by Parse, like CACHE_SIZE=256MiB.
+// It returns def if the variable is unset or empty. The returned error is an *EnvError.
+func Getenv(name string, def Bytes) (Bytes, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || strings.TrimSpace(value) == "" {
+		return def, nil
+	}
+	b, err := Parse(value)
+	if err != nil {
+		return def, &EnvError{Name: name, Value: value, Err: err.(*ParseError).Err}
+	}
+	return b, nil
+}
+
+// LoadEnv sets the Bytes fields of the struct pointed to by v from the environment variables
+// named by their "env" tags, like
+//
+//	CacheSize units.Bytes `env:"CACHE_SIZE"`
+//
+// Fields whose variables are unset or empty keep their values, so defaults can be set beforehand.
+// A nil *Bytes field is allocated when its variable is set. Nested structs and pointers to them
+// are loaded recursively, each struct pointed to is loaded once even if it is referenced again.
+//
+// The returned error is ValidationErrors listing every invalid variable as an *EnvError, or nil.
+func LoadEnv(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("units: cannot load %T, want a pointer to struct", v)
+	}
+	var errs ValidationErrors
+	visited := visitedSet{}
+	visited.first(rv)
+	loadEnvStruct(rv.Elem(), "", visited, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func loadEnvStruct(rv reflect.Value, path string, visited visitedSet, errs *ValidationErrors) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		fv := rv.Field(i)
+		name, tagged := field.Tag.Lookup("env")
+		if !tagged {
+			if fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct && !fv.IsNil() {
+				if !visited.first(fv) {
+					// loaded already, or a cycle
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				loadEnvStruct(fv, fieldPath, visited, errs)
+			}
+			continue
+		}
+
+		isPtr := fv.Kind() == reflect.Ptr
+		typ := fv.Type()
+		if isPtr {
+			typ = typ.Elem()
+		}
+		if typ != bytesType {
+			*errs = append(*errs, &FieldError{Path: fieldPath, Err: fmt.Errorf("units: env tag on unsupported type %s", fv.Type())})
+			continue
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		b, err := Getenv(name, 0)
+		if err != nil {
+			*errs = append(*errs, &FieldError{Path: fieldPath, Err: err})
+			continue
+		}
+		if isPtr {
+			if fv.IsNil() {
+				fv.Set(reflect.New(bytesType))
+			}
+			fv = fv.Elem()
+		}
+		fv.SetUint(uint64(b))
+	}
+}
@@ -0,0 +1,133 @@
+package units
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setenv(t *testing.T, env map[string]string) {
+	for name, value := range env {
+		require.NoError(t, os.Setenv(name, value))
+	}
+	t.Cleanup(func() {
+		for name := range env {
+			os.Unsetenv(name)
+		}
+	})
+}
+
+func TestGetenv(t *testing.T) {
+	setenv(t, map[string]string{
+		"UNITS_TEST_SIZE":     "256MiB",
+		"UNITS_TEST_EMPTY":    " ",
+		"UNITS_TEST_INVALID":  "256XB",
+		"UNITS_TEST_OVERFLOW": "20000000TiB",
+	})
+
+	b, err := Getenv("UNITS_TEST_SIZE", MiB)
+	require.NoError(t, err)
+	assert.Equal(t, 256*MiB, b)
+
+	b, err = Getenv("UNITS_TEST_UNSET", MiB)
+	require.NoError(t, err)
+	assert.Equal(t, MiB, b)
+
+	b, err = Getenv("UNITS_TEST_EMPTY", MiB)
+	require.NoError(t, err)
+	assert.Equal(t, MiB, b)
+
+	b, err = Getenv("UNITS_TEST_INVALID", MiB)
+	assert.Equal(t, MiB, b)
+	assert.EqualError(t, err, `units: parsing $UNITS_TEST_INVALID="256XB": invalid syntax (accepted units: `+AcceptedUnits+")")
+	var eerr *EnvError
+	require.True(t, errors.As(err, &eerr))
+	assert.Equal(t, EnvError{Name: "UNITS_TEST_INVALID", Value: "256XB", Err: ErrSyntax}, *eerr)
+
+	_, err = Getenv("UNITS_TEST_OVERFLOW", MiB)
+	assert.True(t, errors.Is(err, ErrOverflow))
+	assert.EqualError(t, err, `units: parsing $UNITS_TEST_OVERFLOW="20000000TiB": overflow (accepted units: `+AcceptedUnits+")")
+}
+
+func TestLoadEnv(t *testing.T) {
+	setenv(t, map[string]string{
+		"UNITS_TEST_CACHE_SIZE": "256MiB",
+		"UNITS_TEST_PART_SIZE":  "8M",
+		"UNITS_TEST_MAX_UPLOAD": "1.5GB",
+	})
+
+	type upload struct {
+		Max *Bytes `env:"UNITS_TEST_MAX_UPLOAD"`
+	}
+	var config struct {
+		CacheSize Bytes `env:"UNITS_TEST_CACHE_SIZE"`
+		BufSize   Bytes `env:"UNITS_TEST_BUF_SIZE"`
+		Storage   struct {
+			PartSize Bytes `env:"UNITS_TEST_PART_SIZE"`
+		}
+		Upload  *upload
+		Unset   *Bytes `env:"UNITS_TEST_UNSET"`
+		private Bytes  `env:"UNITS_TEST_CACHE_SIZE"`
+	}
+	config.BufSize = 64 * KiB
+	config.Upload = &upload{}
+
+	require.NoError(t, LoadEnv(&config))
+	assert.Equal(t, 256*MiB, config.CacheSize)
+	assert.Equal(t, 64*KiB, config.BufSize)
+	assert.Equal(t, 8*MiB, config.Storage.PartSize)
+	require.NotNil(t, config.Upload.Max)
+	assert.Equal(t, 1500*MB, *config.Upload.Max)
+	assert.Nil(t, config.Unset)
+	assert.Equal(t, Bytes(0), config.private)
+}
+
+func TestLoadEnv_Errors(t *testing.T) {
+	setenv(t, map[string]string{
+		"UNITS_TEST_CACHE_SIZE": "256XB",
+		"UNITS_TEST_PART_SIZE":  "8M",
+		"UNITS_TEST_COUNT":      "1",
+	})
+
+	var config struct {
+		CacheSize Bytes `env:"UNITS_TEST_CACHE_SIZE"`
+		Storage   struct {
+			PartSize Bytes `env:"UNITS_TEST_PART_SIZE"`
+			Count    int   `env:"UNITS_TEST_COUNT"`
+		}
+	}
+	err := LoadEnv(&config)
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 2)
+	assert.Equal(t, "CacheSize", errs[0].Path)
+	assert.True(t, errors.Is(errs[0], ErrSyntax))
+	assert.Contains(t, errs[0].Error(), "$UNITS_TEST_CACHE_SIZE")
+	assert.Equal(t, "Storage.Count", errs[1].Path)
+	assert.Equal(t, 8*MiB, config.Storage.PartSize)
+
+	assert.Error(t, LoadEnv(config))
+	assert.Error(t, LoadEnv(nil))
+}
+
+func TestLoadEnv_cycle(t *testing.T) {
+	setenv(t, map[string]string{"UNITS_TEST_NODE_SIZE": "2KiB"})
+
+	type node struct {
+		Size Bytes `env:"UNITS_TEST_NODE_SIZE"`
+		Next *node
+	}
+	a, b := &node{}, &node{}
+	a.Next, b.Next = b, a
+	require.NoError(t, LoadEnv(a))
+	assert.Equal(t, 2*KiB, a.Size)
+	assert.Equal(t, 2*KiB, b.Size)
+
+	self := &node{}
+	self.Next = self
+	require.NoError(t, LoadEnv(self))
+	assert.Equal(t, 2*KiB, self.Size)
+}
@@ -0,0 +1,41 @@
+package units
+
+import (
+	"expvar"
+	"fmt"
+)
+
+// ExpvarBytes is an expvar.Var holding Bytes. It is rendered as JSON like
+// {"bytes":1536,"human":"1.5kiB"}, where human is formatted with verb 'f'.
+// It is safe for concurrent use.
+type ExpvarBytes struct {
+	v AtomicBytes
+}
+
+// NewExpvarBytes returns a new ExpvarBytes published with the name, like expvar.NewInt.
+func NewExpvarBytes(name string) *ExpvarBytes {
+	v := new(ExpvarBytes)
+	expvar.Publish(name, v)
+	return v
+}
+
+// Value returns the current value.
+func (v *ExpvarBytes) Value() Bytes {
+	return v.v.Load()
+}
+
+// Add adds delta to the value.
+func (v *ExpvarBytes) Add(delta Bytes) {
+	v.v.Add(delta)
+}
+
+// Set sets the value to b.
+func (v *ExpvarBytes) Set(b Bytes) {
+	v.v.Store(b)
+}
+
+// String implements expvar.Var.
+func (v *ExpvarBytes) String() string {
+	b := v.Value()
+	return fmt.Sprintf(`{"bytes":%d,"human":"%f"}`, uint64(b), b)
+}
@@ -0,0 +1,50 @@
+package units
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// publishOnce publishes the variable of TestNewExpvarBytes once, since expvar panics on
+// reusing a name when the test runs again with -count.
+var publishOnce sync.Once
+
+func TestNewExpvarBytes(t *testing.T) {
+	publishOnce.Do(func() {
+		v := NewExpvarBytes("test_expvar_bytes")
+		v.Set(KiB)
+	})
+	v, ok := expvar.Get("test_expvar_bytes").(*ExpvarBytes)
+	assert.True(t, ok)
+	assert.Equal(t, `{"bytes":1024,"human":"1.0kiB"}`, v.String())
+}
+
+func TestExpvarBytes(t *testing.T) {
+	v := new(ExpvarBytes)
+	assert.Equal(t, `{"bytes":0,"human":"0.0B"}`, v.String())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v.Add(KiB)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100*KiB, v.Value())
+
+	v.Set(KiB + 512)
+	assert.Equal(t, `{"bytes":1536,"human":"1.5kiB"}`, v.String())
+
+	var decoded struct {
+		Bytes uint64
+		Human string
+	}
+	assert.NoError(t, json.Unmarshal([]byte(v.String()), &decoded))
+	assert.Equal(t, uint64(1536), decoded.Bytes)
+}
@@ -0,0 +1,175 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// ErrNaN is returned when converting NaN to Bytes.
+var ErrNaN = errors.New("units: not a number")
+
+// RoundingMode tells how a fractional size is rounded to whole bytes.
+type RoundingMode int
+
+const (
+	// RoundNearest rounds to the nearest byte, halves away from zero.
+	RoundNearest RoundingMode = iota
+	// RoundNearestEven rounds to the nearest byte, halves to even.
+	RoundNearestEven
+	// RoundDown rounds towards negative infinity, like Floor.
+	RoundDown
+	// RoundUp rounds towards positive infinity, like Ceil.
+	RoundUp
+)
+
+func (m RoundingMode) round(x float64) float64 {
+	switch m {
+	case RoundNearestEven:
+		return math.RoundToEven(x)
+	case RoundDown:
+		return math.Floor(x)
+	case RoundUp:
+		return math.Ceil(x)
+	default:
+		return math.Round(x)
+	}
+}
+
+// roundBig rounds x to an integer like round does.
+func (m RoundingMode) roundBig(x *big.Float) *big.Int {
+	i, _ := x.Int(nil)
+	frac := new(big.Float).Sub(x, new(big.Float).SetInt(i))
+	half := big.NewFloat(0.5)
+	var up bool // whether to round away from zero
+	switch m {
+	case RoundNearestEven:
+		c := new(big.Float).Abs(frac).Cmp(half)
+		up = c > 0 || c == 0 && i.Bit(0) == 1
+	case RoundDown:
+		up = frac.Sign() < 0
+	case RoundUp:
+		up = frac.Sign() > 0
+	default:
+		up = new(big.Float).Abs(frac).Cmp(half) >= 0
+	}
+	if up && frac.Sign() != 0 {
+		i.Add(i, big.NewInt(int64(frac.Sign())))
+	}
+	return i
+}
+
+// FloatBytes is a size which can be fractional or negative, like averages, ratios and differences.
+//
+// It is formatted with the same verbs and flags as Bytes, where the unit is chosen by the absolute value.
+// NaN and infinities are formatted as "NaN", "+Inf" and "-Inf" without a unit.
+type FloatBytes float64
+
+// Add returns v+o.
+func (v FloatBytes) Add(o FloatBytes) FloatBytes {
+	return v + o
+}
+
+// Sub returns v-o.
+func (v FloatBytes) Sub(o FloatBytes) FloatBytes {
+	return v - o
+}
+
+// Mul returns v multiplied by f.
+func (v FloatBytes) Mul(f float64) FloatBytes {
+	return FloatBytes(float64(v) * f)
+}
+
+// Div returns v divided by f.
+func (v FloatBytes) Div(f float64) FloatBytes {
+	return FloatBytes(float64(v) / f)
+}
+
+// Ratio returns v/o.
+func (v FloatBytes) Ratio(o FloatBytes) float64 {
+	return float64(v) / float64(o)
+}
+
+// Bytes converts v to Bytes rounded by mode. It returns ErrNaN for NaN, ErrUnderflow for a negative result,
+// and ErrOverflow if the result exceeds the maximum of Bytes.
+func (v FloatBytes) Bytes(mode RoundingMode) (Bytes, error) {
+	return floatToBytes(float64(v), mode)
+}
+
+func floatToBytes(x float64, mode RoundingMode) (Bytes, error) {
+	if math.IsNaN(x) {
+		return 0, ErrNaN
+	}
+	x = mode.round(x)
+	switch {
+	case x < 0:
+		return 0, ErrUnderflow
+	case x >= math.MaxUint64:
+		// float64(math.MaxUint64) is 2^64 which is out of range
+		return 0, ErrOverflow
+	}
+	return Bytes(x), nil
+}
+
+// Format implements fmt.Formatter, see package document for the verbs and flags.
+func (v FloatBytes) Format(f fmt.State, verb rune) {
+	x := float64(v)
+	width, hasWidth := f.Width()
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		s := "NaN"
+		if math.IsInf(x, 1) {
+			s = "+Inf"
+		} else if math.IsInf(x, -1) {
+			s = "-Inf"
+		}
+		fmt.Fprintf(f, "%*s", width, s)
+		return
+	}
+	precision := 1
+	if p, ok := f.Precision(); ok {
+		precision = p
+	}
+
+	mags := magnitudes[f.Flag('#')]
+	mag := B
+	switch verb {
+	case 's', 'v', 'f':
+		mag = mags[len(mags)-1]
+		if abs := math.Abs(x); abs < float64(mag) {
+			if f.Flag('#') {
+				mag = Bytes(abs).decimalMagnitude()
+			} else {
+				mag = Bytes(abs).magnitude()
+			}
+		}
+	case 'k':
+		mag = mags[1]
+	case 'm':
+		mag = mags[2]
+	case 'g':
+		mag = mags[3]
+	case 't':
+		mag = mags[4]
+	case 'd':
+		fmt.Fprintf(f, "%*.0f", width, trunc(x))
+		return
+	}
+
+	if hasWidth && !f.Flag(' ') {
+		width -= len(unitNames[mag])
+	}
+	if verb == 'f' {
+		fmt.Fprintf(f, "%*.*f", width, precision, x/float64(mag))
+	} else {
+		fmt.Fprintf(f, "%*.0f", width, trunc(x/float64(mag)))
+	}
+	if !f.Flag(' ') {
+		f.Write(unitNames[mag])
+	}
+}
+
+// trunc is math.Trunc but never returns negative zero, which would be formatted as "-0".
+func trunc(x float64) float64 {
+	return math.Trunc(x) + 0
+}
@@ -0,0 +1,88 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloatBytes_Format(t *testing.T) {
+	tests := []struct {
+		format string
+		v      FloatBytes
+		want   string
+	}{
+		{format: "%f", v: 1.5, want: "1.5B"},
+		{format: "%.2f", v: 0.25, want: "0.25B"},
+		{format: "%f", v: 1536, want: "1.5kiB"},
+		{format: "%#f", v: 1536, want: "1.5kB"},
+		{format: "%s", v: 1536, want: "1kiB"},
+		{format: "%v", v: 2.5 * FloatBytes(MiB), want: "2MiB"},
+		{format: "%f", v: -1536, want: "-1.5kiB"},
+		{format: "%s", v: -0.5, want: "0B"},
+		{format: "%f", v: 3e20, want: "272848410.5TiB"},
+		{format: "%k", v: 3 * FloatBytes(MiB), want: "3072kiB"},
+		{format: "%#m", v: 3 * FloatBytes(MB), want: "3MB"},
+		{format: "%d", v: 1536.7, want: "1536"},
+		{format: "%b", v: 1536.7, want: "1536B"},
+		{format: "%8.2f", v: 1536, want: " 1.50kiB"},
+		{format: "% 8.2f", v: 1536, want: "    1.50"},
+		{format: "% f", v: 1536, want: "1.5"},
+		{format: "%f", v: FloatBytes(math.NaN()), want: "NaN"},
+		{format: "%6f", v: FloatBytes(math.Inf(1)), want: "  +Inf"},
+		{format: "%s", v: FloatBytes(math.Inf(-1)), want: "-Inf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, fmt.Sprintf(tt.format, tt.v))
+		})
+	}
+}
+
+func TestFloatBytes_Format_consistent(t *testing.T) {
+	// FloatBytes of whole bytes formats the same as Bytes
+	for _, b := range []Bytes{0, 1023, KiB + 1, 3*MiB + 7, 5 * GiB, 7*TiB + 1, 1234 * TB} {
+		for _, format := range []string{"%f", "%#.3f", "%s", "%#v", "%k", "%#g", "%10.2f", "% 7s", "%d", "%b"} {
+			assert.Equal(t, fmt.Sprintf(format, b), fmt.Sprintf(format, FloatBytes(b)), "%s of %d", format, b)
+		}
+	}
+}
+
+func TestFloatBytes_arithmetic(t *testing.T) {
+	v := FloatBytes(KiB)
+	assert.Equal(t, FloatBytes(1536), v.Add(512))
+	assert.Equal(t, FloatBytes(-512), v.Sub(1536))
+	assert.Equal(t, FloatBytes(1.5), FloatBytes(3).Div(2))
+	assert.Equal(t, FloatBytes(3072), v.Mul(3))
+	assert.Equal(t, 0.25, FloatBytes(256).Ratio(v))
+}
+
+func TestFloatBytes_Bytes(t *testing.T) {
+	tests := []struct {
+		v       FloatBytes
+		mode    RoundingMode
+		want    Bytes
+		wantErr error
+	}{
+		{v: 1.5, mode: RoundNearest, want: 2},
+		{v: 2.5, mode: RoundNearest, want: 3},
+		{v: 2.5, mode: RoundNearestEven, want: 2},
+		{v: 1.9, mode: RoundDown, want: 1},
+		{v: 1.1, mode: RoundUp, want: 2},
+		{v: -0.4, mode: RoundNearest, want: 0},
+		{v: -0.6, mode: RoundNearest, wantErr: ErrUnderflow},
+		{v: -0.6, mode: RoundUp, want: 0},
+		{v: -0.1, mode: RoundDown, wantErr: ErrUnderflow},
+		{v: 1 << 63, mode: RoundNearest, want: 1 << 63},
+		{v: 1 << 64, mode: RoundNearest, wantErr: ErrOverflow},
+		{v: FloatBytes(math.Inf(1)), mode: RoundDown, wantErr: ErrOverflow},
+		{v: FloatBytes(math.NaN()), mode: RoundNearest, wantErr: ErrNaN},
+	}
+	for _, tt := range tests {
+		got, err := tt.v.Bytes(tt.mode)
+		assert.Equal(t, tt.wantErr, err, "%v", float64(tt.v))
+		assert.Equal(t, tt.want, got, "%v", float64(tt.v))
+	}
+}
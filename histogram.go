@@ -0,0 +1,180 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrHistogramMismatch is returned when merging histograms with different buckets.
+var ErrHistogramMismatch = errors.New("units: histograms have different buckets")
+
+// Histogram counts values in buckets. It is safe for concurrent use.
+//
+// Bucket i covers [bounds[i-1], bounds[i]), the first bucket starts from 0 and the last one is unbounded.
+type Histogram struct {
+	// sum is accessed atomically, keep it first for alignment on 32-bit platforms
+	sum     uint64
+	bounds  []Bytes
+	counts  []uint64
+	decimal bool
+}
+
+// HistogramBucket is a snapshot of a bucket of Histogram.
+type HistogramBucket struct {
+	// Lower is the inclusive lower bound.
+	Lower Bytes
+	// Upper is the exclusive upper bound, it is zero for the last bucket which is unbounded.
+	Upper Bytes
+	Count uint64
+}
+
+// NewHistogram returns a Histogram with the given bucket bounds, which must be positive and increasing.
+func NewHistogram(bounds ...Bytes) *Histogram {
+	for i, b := range bounds {
+		if b == 0 || i > 0 && b <= bounds[i-1] {
+			panic("units: histogram bounds must be positive and increasing")
+		}
+	}
+	return &Histogram{
+		bounds: append([]Bytes(nil), bounds...),
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// NewBinaryHistogram returns a Histogram whose buckets are the binary orders of magnitude,
+// i.e. [0, 1kiB), [1kiB, 1MiB), ... [1TiB, ∞).
+func NewBinaryHistogram() *Histogram {
+	return NewHistogram(binaryMagnitudes[1:]...)
+}
+
+// NewDecimalHistogram returns a Histogram whose buckets are the decimal orders of magnitude,
+// i.e. [0, 1kB), [1kB, 1MB), ... [1TB, ∞). Its buckets are labeled with decimal units.
+func NewDecimalHistogram() *Histogram {
+	h := NewHistogram(decimalMagnitudes[1:]...)
+	h.decimal = true
+	return h
+}
+
+// NewLog2Histogram returns a Histogram whose buckets are the powers of two from 1B to max,
+// i.e. [0, 1B), [1B, 2B), [2B, 4B), ... [max, ∞).
+func NewLog2Histogram(max Bytes) *Histogram {
+	var bounds []Bytes
+	for b := B; b != 0 && b <= max; b <<= 1 {
+		bounds = append(bounds, b)
+	}
+	return NewHistogram(bounds...)
+}
+
+// Observe records b.
+func (h *Histogram) Observe(b Bytes) {
+	i := sort.Search(len(h.bounds), func(i int) bool { return b < h.bounds[i] })
+	atomic.AddUint64(&h.counts[i], 1)
+	atomic.AddUint64(&h.sum, uint64(b))
+}
+
+// Merge adds the counts of o to h, they must have the same buckets.
+func (h *Histogram) Merge(o *Histogram) error {
+	if len(h.bounds) != len(o.bounds) {
+		return ErrHistogramMismatch
+	}
+	for i := range h.bounds {
+		if h.bounds[i] != o.bounds[i] {
+			return ErrHistogramMismatch
+		}
+	}
+	for i := range o.counts {
+		atomic.AddUint64(&h.counts[i], atomic.LoadUint64(&o.counts[i]))
+	}
+	atomic.AddUint64(&h.sum, atomic.LoadUint64(&o.sum))
+	return nil
+}
+
+// Buckets returns the snapshot of all buckets.
+func (h *Histogram) Buckets() []HistogramBucket {
+	buckets := make([]HistogramBucket, len(h.counts))
+	for i := range h.counts {
+		buckets[i].Count = atomic.LoadUint64(&h.counts[i])
+		if i > 0 {
+			buckets[i].Lower = h.bounds[i-1]
+		}
+		if i < len(h.bounds) {
+			buckets[i].Upper = h.bounds[i]
+		}
+	}
+	return buckets
+}
+
+// Count returns the number of observed values.
+func (h *Histogram) Count() uint64 {
+	var n uint64
+	for i := range h.counts {
+		n += atomic.LoadUint64(&h.counts[i])
+	}
+	return n
+}
+
+// Sum returns the sum of observed values, it wraps around on overflow.
+func (h *Histogram) Sum() Bytes {
+	return Bytes(atomic.LoadUint64(&h.sum))
+}
+
+// histogramBarWidth is the width of the bar of the largest bucket in the text rendering.
+const histogramBarWidth = 40
+
+// WriteTo writes a text rendering of h to w, one bucket per line, like
+//
+//	[1kiB, 1MiB)  12  ########
+func (h *Histogram) WriteTo(w io.Writer) (int64, error) {
+	format := "%s"
+	if h.decimal {
+		format = "%#s"
+	}
+	buckets := h.Buckets()
+	labels := make([]string, len(buckets))
+	var labelWidth, countWidth int
+	var maxCount uint64
+	for i, bucket := range buckets {
+		upper := "∞"
+		if i < len(h.bounds) {
+			upper = fmt.Sprintf(format, bucket.Upper)
+		}
+		labels[i] = "[" + fmt.Sprintf(format, bucket.Lower) + ", " + upper + ")"
+		if n := len([]rune(labels[i])); n > labelWidth {
+			labelWidth = n
+		}
+		if n := len(fmt.Sprint(bucket.Count)); n > countWidth {
+			countWidth = n
+		}
+		if bucket.Count > maxCount {
+			maxCount = bucket.Count
+		}
+	}
+
+	var written int64
+	for i, bucket := range buckets {
+		bar := 0
+		if maxCount > 0 {
+			bar = int(math.Round(float64(bucket.Count) / float64(maxCount) * histogramBarWidth))
+		}
+		padding := strings.Repeat(" ", labelWidth-len([]rune(labels[i])))
+		line := fmt.Sprintf("%s%s  %*d  %s", labels[i], padding, countWidth, bucket.Count, strings.Repeat("#", bar))
+		n, err := io.WriteString(w, strings.TrimRight(line, " ")+"\n")
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// String returns the text rendering of h.
+func (h *Histogram) String() string {
+	var sb strings.Builder
+	h.WriteTo(&sb)
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+package units
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_Observe(t *testing.T) {
+	h := NewBinaryHistogram()
+	var wg sync.WaitGroup
+	for _, b := range []Bytes{0, 1023, KiB, MiB - 1, MiB, 5 * GiB, 2 * TiB} {
+		wg.Add(1)
+		go func(b Bytes) {
+			defer wg.Done()
+			h.Observe(b)
+		}(b)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []HistogramBucket{
+		{Lower: 0, Upper: KiB, Count: 2},
+		{Lower: KiB, Upper: MiB, Count: 2},
+		{Lower: MiB, Upper: GiB, Count: 1},
+		{Lower: GiB, Upper: TiB, Count: 1},
+		{Lower: TiB, Upper: 0, Count: 1},
+	}, h.Buckets())
+	assert.Equal(t, uint64(7), h.Count())
+	assert.Equal(t, 2*TiB+5*GiB+2*MiB+KiB+1022, h.Sum())
+}
+
+func TestNewLog2Histogram(t *testing.T) {
+	h := NewLog2Histogram(4 * KiB)
+	assert.Len(t, h.Buckets(), 14)
+	h.Observe(3)
+	h.Observe(4 * KiB)
+	buckets := h.Buckets()
+	assert.Equal(t, HistogramBucket{Lower: 2, Upper: 4, Count: 1}, buckets[2])
+	assert.Equal(t, HistogramBucket{Lower: 4 * KiB, Count: 1}, buckets[13])
+
+	assert.Len(t, NewLog2Histogram(1<<63+1).Buckets(), 65)
+}
+
+func TestNewHistogram_invalid(t *testing.T) {
+	assert.Panics(t, func() { NewHistogram(0) })
+	assert.Panics(t, func() { NewHistogram(KiB, KiB) })
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	h, o := NewDecimalHistogram(), NewDecimalHistogram()
+	h.Observe(KB)
+	o.Observe(KB + 1)
+	o.Observe(TB)
+	assert.NoError(t, h.Merge(o))
+	assert.Equal(t, uint64(3), h.Count())
+	assert.Equal(t, uint64(2), h.Buckets()[1].Count)
+	assert.Equal(t, TB+2*KB+1, h.Sum())
+
+	assert.Equal(t, ErrHistogramMismatch, h.Merge(NewBinaryHistogram()))
+	assert.Equal(t, ErrHistogramMismatch, h.Merge(NewHistogram(KB)))
+}
+
+func TestHistogram_String(t *testing.T) {
+	h := NewBinaryHistogram()
+	for i := 0; i < 10; i++ {
+		h.Observe(Bytes(i) * 100 * KiB)
+	}
+	h.Observe(3 * GiB)
+	assert.Equal(t, ""+
+		"[0B, 1kiB)    1  ####\n"+
+		"[1kiB, 1MiB)  9  ########################################\n"+
+		"[1MiB, 1GiB)  0\n"+
+		"[1GiB, 1TiB)  1  ####\n"+
+		"[1TiB, ∞)     0\n", h.String())
+
+	h = NewDecimalHistogram()
+	h.Observe(KB)
+	assert.Equal(t, ""+
+		"[0B, 1kB)   0\n"+
+		"[1kB, 1MB)  1  ########################################\n"+
+		"[1MB, 1GB)  0\n"+
+		"[1GB, 1TB)  0\n"+
+		"[1TB, ∞)    0\n", h.String())
+}
@@ -0,0 +1,192 @@
+package units
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket that limits throughput to a rate of bytes per second.
+//
+// The bucket is refilled at the rate and holds at most burst bytes.
+// A rate of zero disables the limit. It is safe for concurrent use.
+type Limiter struct {
+	clock Clock
+
+	mu     sync.Mutex
+	rate   Bytes
+	burst  Bytes
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a Limiter with the given rate per second and a burst equal to rate.
+func NewLimiter(rate Bytes) *Limiter {
+	return NewLimiterWithClock(rate, SystemClock)
+}
+
+// NewLimiterWithClock is like NewLimiter but measures time with clock.
+func NewLimiterWithClock(rate Bytes, clock Clock) *Limiter {
+	return &Limiter{
+		clock:  clock,
+		rate:   rate,
+		burst:  rate,
+		tokens: float64(rate),
+		last:   clock.Now(),
+	}
+}
+
+// Limit returns the rate per second.
+func (l *Limiter) Limit() Bytes {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// Burst returns the capacity of the bucket.
+func (l *Limiter) Burst() Bytes {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.burst
+}
+
+// SetLimit changes the rate per second. Callers already waiting keep their schedule.
+func (l *Limiter) SetLimit(rate Bytes) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(l.clock.Now())
+	l.rate = rate
+}
+
+// SetBurst changes the capacity of the bucket.
+func (l *Limiter) SetBurst(burst Bytes) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(l.clock.Now())
+	l.burst = burst
+	if l.tokens > float64(burst) {
+		l.tokens = float64(burst)
+	}
+}
+
+// refill adds the tokens accumulated since last refill. l.mu must be held.
+func (l *Limiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * float64(l.rate)
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+	}
+	l.last = now
+}
+
+// WaitN blocks until n bytes are allowed or ctx is done.
+// Requests larger than the burst are served in several rounds.
+func (l *Limiter) WaitN(ctx context.Context, n Bytes) error {
+	for n > 0 {
+		chunk := n
+		if burst := l.Burst(); burst > 0 && chunk > burst {
+			chunk = burst
+		}
+		if err := l.wait(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+func (l *Limiter) wait(ctx context.Context, n Bytes) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	if l.rate == 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	l.refill(l.clock.Now())
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-l.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		// give back the tokens which are not consumed
+		l.mu.Lock()
+		l.refill(l.clock.Now())
+		l.tokens += float64(n)
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Reader returns a reader that reads from r no faster than l allows.
+func (l *Limiter) Reader(ctx context.Context, r io.Reader) io.Reader {
+	return &limitedReader{ctx: ctx, r: r, l: l}
+}
+
+// Writer returns a writer that writes to w no faster than l allows.
+func (l *Limiter) Writer(ctx context.Context, w io.Writer) io.Writer {
+	return &limitedWriter{ctx: ctx, w: w, l: l}
+}
+
+type limitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *Limiter
+}
+
+func (r *limitedReader) Read(b []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if burst := r.l.Burst(); burst > 0 && Bytes(len(b)) > burst {
+		b = b[:burst]
+	}
+	n, err := r.r.Read(b)
+	if n > 0 {
+		if werr := r.l.WaitN(r.ctx, Bytes(n)); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+type limitedWriter struct {
+	ctx context.Context
+	w   io.Writer
+	l   *Limiter
+}
+
+func (w *limitedWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		chunk := b
+		if burst := w.l.Burst(); burst > 0 && Bytes(len(chunk)) > burst {
+			chunk = chunk[:burst]
+		}
+		if err := w.l.WaitN(w.ctx, Bytes(len(chunk))); err != nil {
+			return written, err
+		}
+		n, err := w.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
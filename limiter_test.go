@@ -0,0 +1,120 @@
+package units
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stuckClock never fires, so waiting can only be ended by the context.
+type stuckClock struct {
+	fakeClock
+}
+
+func (c *stuckClock) After(time.Duration) <-chan time.Time { return nil }
+
+func TestLimiter_WaitN(t *testing.T) {
+	start := time.Unix(0, 0)
+	tests := []struct {
+		name  string
+		rate  Bytes
+		burst Bytes
+		waits []Bytes
+		want  time.Duration
+	}{
+		{
+			name:  "within burst",
+			rate:  1 * MiB,
+			burst: 1 * MiB,
+			waits: []Bytes{512 * KiB, 512 * KiB},
+			want:  0,
+		},
+		{
+			name:  "exceed burst",
+			rate:  1 * MiB,
+			burst: 1 * MiB,
+			waits: []Bytes{1 * MiB, 2 * MiB},
+			want:  2 * time.Second,
+		},
+		{
+			name:  "larger than burst",
+			rate:  1 * MiB,
+			burst: 256 * KiB,
+			waits: []Bytes{1 * MiB},
+			want:  750 * time.Millisecond,
+		},
+		{
+			name:  "unlimited",
+			rate:  0,
+			waits: []Bytes{1 * TiB},
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: start}
+			l := NewLimiterWithClock(tt.rate, clock)
+			l.SetBurst(tt.burst)
+			for _, n := range tt.waits {
+				assert.NoError(t, l.WaitN(context.Background(), n))
+			}
+			assert.Equal(t, tt.want, clock.now.Sub(start))
+		})
+	}
+}
+
+func TestLimiter_SetLimit(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &fakeClock{now: start}
+	l := NewLimiterWithClock(1*MiB, clock)
+	assert.Equal(t, 1*MiB, l.Limit())
+	assert.Equal(t, 1*MiB, l.Burst())
+
+	assert.NoError(t, l.WaitN(context.Background(), 1*MiB))
+	l.SetLimit(4 * MiB)
+	assert.NoError(t, l.WaitN(context.Background(), 1*MiB))
+	assert.Equal(t, 250*time.Millisecond, clock.now.Sub(start))
+}
+
+func TestLimiter_Cancel(t *testing.T) {
+	clock := &stuckClock{fakeClock{now: time.Unix(0, 0)}}
+	l := NewLimiterWithClock(1*KiB, clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, l.WaitN(ctx, 1*B), context.Canceled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, l.WaitN(ctx, 1*KiB))
+	assert.ErrorIs(t, l.WaitN(ctx, 512*B), context.DeadlineExceeded)
+	// the tokens of the cancelled wait are given back
+	l.mu.Lock()
+	assert.Equal(t, float64(0), l.tokens)
+	l.mu.Unlock()
+}
+
+func TestLimiter_ReaderWriter(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &fakeClock{now: start}
+	l := NewLimiterWithClock(1*KiB, clock)
+	data := strings.Repeat("x", 4*1024)
+
+	var dst bytes.Buffer
+	n, err := io.Copy(&dst, l.Reader(context.Background(), strings.NewReader(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+	assert.Equal(t, 3*time.Second, clock.now.Sub(start))
+
+	dst.Reset()
+	w := l.Writer(context.Background(), &dst)
+	written, err := w.Write([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), written)
+	assert.Equal(t, data, dst.String())
+	assert.Equal(t, 7*time.Second, clock.now.Sub(start))
+}
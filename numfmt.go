@@ -0,0 +1,160 @@
+package units
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Numfmt is a stream filter that humanizes byte counts in columns of text, like GNU "numfmt --to=iec".
+//
+// Lines are split into fields by blanks, or by Delimiter if set. The selected fields are converted,
+// others, including the selected ones which are not valid numbers, are passed through unchanged.
+// When splitting by blanks, the converted fields are padded to keep the columns aligned, and a field
+// which grows takes the room from the blanks around it. If there is no room, the rest of the line shifts.
+// Lines can be of any length, and line endings are kept as is.
+type Numfmt struct {
+	// Fields are the 1-based indexes of fields to convert. Field 1 is converted if neither Fields nor Match is set.
+	Fields []int
+	// Match selects the fields whose text matches it, in addition to Fields.
+	Match *regexp.Regexp
+	// Delimiter separates the fields instead of blanks.
+	Delimiter string
+	// Header is the number of leading lines passed through unchanged.
+	Header int
+	// Format is used to format the converted byte counts, default is "%f".
+	Format string
+	// Reverse converts human-readable sizes accepted by Parse back to byte counts, like "numfmt --from=iec".
+	Reverse bool
+}
+
+// Filter converts lines from r and writes them to w.
+func (n *Numfmt) Filter(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for line := 0; ; line++ {
+		text, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if text != "" {
+			text, eol := splitLineEnding(text)
+			if line >= n.Header {
+				text = n.Line(text)
+			}
+			bw.WriteString(text)
+			if _, err := bw.WriteString(eol); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return bw.Flush()
+		}
+	}
+}
+
+// splitLineEnding splits the trailing "\n" or "\r\n" from line.
+func splitLineEnding(line string) (text, eol string) {
+	text = strings.TrimSuffix(line, "\n")
+	text = strings.TrimSuffix(text, "\r")
+	return text, line[len(text):]
+}
+
+// Line converts a single line without the trailing newline.
+func (n *Numfmt) Line(line string) string {
+	if n.Delimiter != "" {
+		fields := strings.Split(line, n.Delimiter)
+		for i, field := range fields {
+			if converted, ok := n.convertField(i+1, field); ok {
+				fields[i] = converted
+			}
+		}
+		return strings.Join(fields, n.Delimiter)
+	}
+
+	// tokens alternate between blanks and fields, starting with blanks which may be empty
+	tokens := splitBlanks(line)
+	for i := 1; i < len(tokens); i += 2 {
+		field := tokens[i]
+		converted, ok := n.convertField((i+1)/2, field)
+		if !ok {
+			continue
+		}
+		if pad := len(field) - len(converted); pad > 0 {
+			// right-align in the original width
+			converted = strings.Repeat(" ", pad) + converted
+		} else if pad < 0 {
+			// take the room from the leading blanks, and then the trailing ones
+			need := -pad - takeBlanks(&tokens[i-1], -pad)
+			if i+1 < len(tokens) {
+				takeBlanks(&tokens[i+1], need)
+			}
+		}
+		tokens[i] = converted
+	}
+	return strings.Join(tokens, "")
+}
+
+func (n *Numfmt) selected(index int, field string) bool {
+	if len(n.Fields) == 0 && n.Match == nil {
+		return index == 1
+	}
+	for _, i := range n.Fields {
+		if i == index {
+			return true
+		}
+	}
+	return n.Match != nil && n.Match.MatchString(field)
+}
+
+func (n *Numfmt) convertField(index int, field string) (string, bool) {
+	if !n.selected(index, field) {
+		return "", false
+	}
+	text := strings.TrimSpace(field)
+	if n.Reverse {
+		b, err := Parse(text)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatUint(uint64(b), 10), true
+	}
+	v, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return "", false
+	}
+	format := n.Format
+	if format == "" {
+		format = "%f"
+	}
+	return fmt.Sprintf(format, Bytes(v)), true
+}
+
+// takeBlanks removes up to n blanks from *blanks but keeps at least one, and returns the number removed.
+func takeBlanks(blanks *string, n int) int {
+	if n > len(*blanks)-1 {
+		n = len(*blanks) - 1
+	}
+	if n <= 0 {
+		return 0
+	}
+	*blanks = (*blanks)[n:]
+	return n
+}
+
+// splitBlanks splits s into blanks and non-blanks alternately, the first token is always blanks.
+func splitBlanks(s string) []string {
+	var tokens []string
+	start, blank := 0, true
+	for i := 0; i < len(s); i++ {
+		isBlank := s[i] == ' ' || s[i] == '\t'
+		if isBlank != blank {
+			tokens = append(tokens, s[start:i])
+			start, blank = i, isBlank
+		}
+	}
+	return append(tokens, s[start:])
+}
@@ -0,0 +1,115 @@
+package units
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumfmt_Line(t *testing.T) {
+	tests := []struct {
+		name string
+		n    Numfmt
+		line string
+		want string
+	}{
+		{
+			name: "default field",
+			n:    Numfmt{},
+			line: "1536 a.txt",
+			want: "1.5kiB a.txt",
+		},
+		{
+			name: "ls",
+			n:    Numfmt{Fields: []int{5}},
+			line: "-rw-r--r-- 1 root root      1048576 Oct 19 14:27 big.bin",
+			want: "-rw-r--r-- 1 root root       1.0MiB Oct 19 14:27 big.bin",
+		},
+		{
+			name: "wider than field",
+			n:    Numfmt{Fields: []int{2}},
+			line: "a    1000 b",
+			want: "a 1000.0B b",
+		},
+		{
+			name: "room from trailing blanks",
+			n:    Numfmt{Fields: []int{2}},
+			line: "root 1    Oct",
+			want: "root 1.0B Oct",
+		},
+		{
+			name: "room from both sides",
+			n:    Numfmt{Fields: []int{2}},
+			line: "a  1000  b",
+			want: "a 1000.0B b",
+		},
+		{
+			// there is no room, so the rest of the line shifts
+			name: "no room",
+			n:    Numfmt{Fields: []int{2}},
+			line: "root 1 Oct",
+			want: "root 1.0B Oct",
+		},
+		{
+			name: "not a number",
+			n:    Numfmt{Fields: []int{1, 2}},
+			line: "total 4096",
+			want: "total 4.0kiB",
+		},
+		{
+			name: "match",
+			n:    Numfmt{Match: regexp.MustCompile(`^\d{4,}$`), Format: "%s"},
+			line: "  12 2048  10240",
+			want: "  12 2kiB  10kiB",
+		},
+		{
+			name: "delimiter",
+			n:    Numfmt{Fields: []int{2}, Delimiter: ",", Format: "%#f"},
+			line: "a.txt,1500,x",
+			want: "a.txt,1.5kB,x",
+		},
+		{
+			name: "reverse",
+			n:    Numfmt{Fields: []int{1}, Reverse: true},
+			line: "1.5kiB a.txt",
+			want: "  1536 a.txt",
+		},
+		{
+			name: "reverse invalid",
+			n:    Numfmt{Reverse: true},
+			line: "1.5PiB a.txt",
+			want: "1.5PiB a.txt",
+		},
+		{
+			name: "tabs",
+			n:    Numfmt{Fields: []int{2}},
+			line: "x\t2097152\t",
+			want: "x\t 2.0MiB\t",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.n.Line(tt.line))
+		})
+	}
+}
+
+func TestNumfmt_Filter(t *testing.T) {
+	in := "SIZE NAME\n1024 a\n2048 b\n"
+	var out bytes.Buffer
+	n := Numfmt{Header: 1, Format: "%s"}
+	assert.NoError(t, n.Filter(strings.NewReader(in), &out))
+	assert.Equal(t, "SIZE NAME\n1kiB a\n2kiB b\n", out.String())
+}
+
+func TestNumfmt_Filter_lines(t *testing.T) {
+	long := strings.Repeat("x", 100*1024)
+	in := "1024 " + long + "\r\n2048 b"
+	var out bytes.Buffer
+	n := Numfmt{Format: "%s"}
+	assert.NoError(t, n.Filter(strings.NewReader(in), &out))
+	assert.Equal(t, "1kiB "+long+"\r\n2kiB b", out.String())
+}
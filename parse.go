@@ -0,0 +1,94 @@
+package units
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrSyntax is returned when the input is not a valid size.
+var ErrSyntax = errors.New("units: invalid syntax")
+
+// ParseError records a failed parse.
+type ParseError struct {
+	// Input is the text being parsed.
+	Input string
+	// Err is the reason, ErrSyntax or ErrOverflow.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return "units: parsing " + strconv.Quote(e.Input) + ": " + reason(e.Err)
+}
+
+// reason returns the message of err without the package prefix, for wrapping errors which have one.
+func reason(err error) string {
+	return strings.TrimPrefix(err.Error(), "units: ")
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// AcceptedUnits lists the units accepted by Parse, for error messages and usage.
+const AcceptedUnits = "B, kiB/KiB, MiB, GiB, TiB, K, M, G, T (binary), kB/KB, MB, GB, TB (decimal)"
+
+// parseUnits maps lower case unit names to magnitudes.
+var parseUnits = map[string]Bytes{
+	"":    B,
+	"b":   B,
+	"k":   KiB,
+	"kib": KiB,
+	"m":   MiB,
+	"mib": MiB,
+	"g":   GiB,
+	"gib": GiB,
+	"t":   TiB,
+	"tib": TiB,
+	"kb":  KB,
+	"mb":  MB,
+	"gb":  GB,
+	"tb":  TB,
+}
+
+// Parse parses a size like "1024", "4kiB", "3.5 GiB" or "1.5MB".
+//
+// Units are case-insensitive, single letter units like "K" are binary. A fractional number
+// is rounded to the nearest byte. The returned error is a *ParseError.
+func Parse(s string) (Bytes, error) {
+	n, err := parseInt(s, func(unit string) (*big.Int, bool) {
+		mag, ok := parseUnits[unit]
+		return new(big.Int).SetUint64(uint64(mag)), ok
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, &ParseError{Input: s, Err: ErrOverflow}
+	}
+	return Bytes(n.Uint64()), nil
+}
+
+// parseInt parses s to bytes with the magnitude of units looked up by the lower case name.
+func parseInt(s string, lookup func(unit string) (*big.Int, bool)) (*big.Int, error) {
+	str := strings.TrimSpace(s)
+	i := 0
+	for i < len(str) && (str[i] >= '0' && str[i] <= '9' || str[i] == '.') {
+		i++
+	}
+	number, unit := str[:i], strings.ToLower(strings.TrimSpace(str[i:]))
+	mag, ok := lookup(unit)
+	if !ok || number == "" || number == "." || strings.Count(number, ".") > 1 {
+		return nil, &ParseError{Input: s, Err: ErrSyntax}
+	}
+
+	r, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return nil, &ParseError{Input: s, Err: ErrSyntax}
+	}
+	r.Mul(r, new(big.Rat).SetInt(mag))
+	// round half up to the nearest byte
+	r.Add(r, big.NewRat(1, 2))
+	return new(big.Int).Quo(r.Num(), r.Denom()), nil
+}
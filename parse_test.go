@@ -0,0 +1,63 @@
+package units
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Bytes
+		wantErr error
+	}{
+		{s: "0", want: 0},
+		{s: "1024", want: KiB},
+		{s: "1024B", want: KiB},
+		{s: "4kiB", want: 4 * KiB},
+		{s: "4KiB", want: 4 * KiB},
+		{s: "4K", want: 4 * KiB},
+		{s: "4kB", want: 4 * KB},
+		{s: "3.5GiB", want: 3*GiB + 512*MiB},
+		{s: " 3.5 GiB ", want: 3*GiB + 512*MiB},
+		{s: "1.5MB", want: 1500 * KB},
+		{s: "2tb", want: 2 * TB},
+		{s: ".5kiB", want: 512},
+		{s: "1.0005kB", want: 1001},
+		{s: "1.0004kB", want: 1000},
+		{s: "16777215TiB", want: 16777215 * TiB},
+		{s: "18446744073709551615", want: 18446744073709551615},
+		{s: "16777216TiB", wantErr: ErrOverflow},
+		{s: "", wantErr: ErrSyntax},
+		{s: "kiB", wantErr: ErrSyntax},
+		{s: ".", wantErr: ErrSyntax},
+		{s: "1.2.3", wantErr: ErrSyntax},
+		{s: "-1", wantErr: ErrSyntax},
+		{s: "1PiB", wantErr: ErrSyntax},
+		{s: "1 2", wantErr: ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := Parse(tt.s)
+			assert.True(t, errors.Is(err, tt.wantErr), "error %v", err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse("1 PiB")
+	assert.EqualError(t, err, `units: parsing "1 PiB": invalid syntax`)
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "1 PiB", perr.Input)
+
+	_, err = Parse("18446744073709551616")
+	assert.EqualError(t, err, `units: parsing "18446744073709551616": overflow`)
+	assert.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = ParseRange("2k..1k")
+	assert.EqualError(t, err, `units: parsing "2k..1k": invalid range`)
+}
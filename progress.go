@@ -0,0 +1,242 @@
+package units
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// Clock tells the current time. It can be replaced to make time-dependent code testable.
+type Clock interface {
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SystemClock is the Clock backed by package time.
+var SystemClock Clock = systemClock{}
+
+// defaultProgressWindow is the smoothing window of throughput when Progress.Window is not set.
+const defaultProgressWindow = 5 * time.Second
+
+// Progress tracks the progress of transferring a known amount of bytes.
+//
+// The zero value is ready to use, but Total should be set to get percentage and ETA.
+// It is safe for concurrent use.
+//
+// If OnUpdate is set with a positive Interval, a timer calls it every Interval from the first Add
+// until the transfer completes, so a stalled transfer keeps being reported. Call Stop if it may not complete.
+type Progress struct {
+	// Total is the expected amount of bytes.
+	Total Bytes
+	// Interval is the time between two OnUpdate calls.
+	// OnUpdate is called on every Add instead if Interval is zero.
+	Interval time.Duration
+	// OnUpdate is called with the latest status. It is always called once when the transfer completes.
+	OnUpdate func(ProgressStatus)
+	// Window is the time span that the throughput is averaged over, default is 5 seconds.
+	Window time.Duration
+	// Clock is used to measure time, default is SystemClock.
+	Clock Clock
+
+	mu       sync.Mutex
+	started  bool
+	start    time.Time
+	last     time.Time
+	done     Bytes
+	rate     float64
+	finished bool
+	// stop ends the timer of OnUpdate, it is nil if there is no timer
+	stop    chan struct{}
+	stopped bool
+}
+
+// ProgressStatus is a snapshot of a Progress.
+type ProgressStatus struct {
+	Done    Bytes
+	Total   Bytes
+	Elapsed time.Duration
+	// Rate is the moving average throughput in bytes per second, it decays while no bytes arrive.
+	Rate Bytes
+	// ETA is the estimated remaining time, it is negative if unknown.
+	ETA time.Duration
+}
+
+// Percent returns the completed percentage in range [0, 100].
+func (s ProgressStatus) Percent() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	if s.Done >= s.Total {
+		return 100
+	}
+	return float64(s.Done) / float64(s.Total) * 100
+}
+
+// String renders s like "1.2GiB / 4.0GiB (30%) 85.3MiB/s ETA 33s".
+func (s ProgressStatus) String() string {
+	eta := "-"
+	if s.ETA >= 0 {
+		eta = s.ETA.Round(time.Second).String()
+	}
+	return fmt.Sprintf("%f / %f (%.0f%%) %f/s ETA %s", s.Done, s.Total, math.Floor(s.Percent()), s.Rate, eta)
+}
+
+// Add records n more bytes transferred.
+func (p *Progress) Add(n Bytes) {
+	p.mu.Lock()
+	now := p.clock().Now()
+	if !p.started {
+		p.started = true
+		p.start, p.last = now, now
+		if p.OnUpdate != nil && p.Interval > 0 {
+			p.stop = make(chan struct{})
+			go p.tick(p.stop)
+		}
+	}
+	p.done += n
+	if dt := now.Sub(p.last); dt > 0 {
+		instant := float64(n) / dt.Seconds()
+		if p.last.Equal(p.start) {
+			p.rate = instant
+		} else {
+			// exponential moving average weighted by the elapsed time
+			alpha := 1 - math.Exp(-float64(dt)/float64(p.window()))
+			p.rate += alpha * (instant - p.rate)
+		}
+		p.last = now
+	}
+
+	notify := p.OnUpdate != nil && !p.finished && p.Interval <= 0
+	if p.Total > 0 && p.done >= p.Total && !p.finished {
+		p.finished = true
+		notify = p.OnUpdate != nil
+		p.stopTimer()
+	}
+	status := p.status(now)
+	p.mu.Unlock()
+
+	if notify {
+		p.OnUpdate(status)
+	}
+}
+
+// Stop stops calling OnUpdate by the timer. It is not needed if the transfer completes.
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	p.stopTimer()
+	p.mu.Unlock()
+}
+
+func (p *Progress) stopTimer() {
+	if p.stop != nil && !p.stopped {
+		p.stopped = true
+		close(p.stop)
+	}
+}
+
+// tick calls OnUpdate every Interval until stop is closed.
+func (p *Progress) tick(stop <-chan struct{}) {
+	for {
+		select {
+		case <-p.clock().After(p.Interval):
+		case <-stop:
+			return
+		}
+		p.mu.Lock()
+		if p.stopped {
+			p.mu.Unlock()
+			return
+		}
+		status := p.status(p.clock().Now())
+		p.mu.Unlock()
+		p.OnUpdate(status)
+	}
+}
+
+// Status returns the current status.
+func (p *Progress) Status() ProgressStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status(p.clock().Now())
+}
+
+func (p *Progress) status(now time.Time) ProgressStatus {
+	rate := p.rate
+	if dt := now.Sub(p.last); p.started && !p.finished && dt > 0 {
+		// nothing arrived since the last Add, which weighs in the average as a zero throughput
+		rate *= math.Exp(-float64(dt) / float64(p.window()))
+	}
+	s := ProgressStatus{Done: p.done, Total: p.Total, Rate: Bytes(rate), ETA: -1}
+	if p.started {
+		s.Elapsed = now.Sub(p.start)
+	}
+	switch {
+	case p.Total > 0 && p.done >= p.Total:
+		s.ETA = 0
+	case p.Total > 0 && rate > 0:
+		// a long stall makes the ETA too far to tell
+		if eta := float64(p.Total-p.done) / rate * float64(time.Second); eta < math.MaxInt64 {
+			s.ETA = time.Duration(eta)
+		}
+	}
+	return s
+}
+
+func (p *Progress) clock() Clock {
+	if p.Clock == nil {
+		return SystemClock
+	}
+	return p.Clock
+}
+
+func (p *Progress) window() time.Duration {
+	if p.Window <= 0 {
+		return defaultProgressWindow
+	}
+	return p.Window
+}
+
+// Reader returns a reader that reads from r and records the read bytes to p.
+func (p *Progress) Reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, p: p}
+}
+
+// Writer returns a writer that writes to w and records the written bytes to p.
+func (p *Progress) Writer(w io.Writer) io.Writer {
+	return &progressWriter{w: w, p: p}
+}
+
+type progressReader struct {
+	r io.Reader
+	p *Progress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if n > 0 {
+		r.p.Add(Bytes(n))
+	}
+	return n, err
+}
+
+type progressWriter struct {
+	w io.Writer
+	p *Progress
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	if n > 0 {
+		w.p.Add(Bytes(n))
+	}
+	return n, err
+}
@@ -0,0 +1,245 @@
+package units
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+// After advances the clock immediately, so that waiting costs no real time.
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.advance(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// manualClock fires the timers only when the test advances it, so that timers in other goroutines
+// can be stepped through.
+type manualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []manualTimer
+	// added receives a value for every timer created by After
+	added chan struct{}
+}
+
+type manualTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{now: time.Unix(0, 0), added: make(chan struct{}, 100)}
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	ch := make(chan time.Time, 1)
+	c.timers = append(c.timers, manualTimer{at: c.now.Add(d), ch: ch})
+	c.mu.Unlock()
+	c.added <- struct{}{}
+	return ch
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			pending = append(pending, timer)
+		} else {
+			timer.ch <- c.now
+		}
+	}
+	c.timers = pending
+}
+
+// waitTimer waits for a timer to be created by After.
+func (c *manualClock) waitTimer(t *testing.T) {
+	select {
+	case <-c.added:
+	case <-time.After(time.Second):
+		t.Fatal("no timer is created")
+	}
+}
+
+func TestProgressStatus_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		status ProgressStatus
+		want   string
+	}{
+		{
+			name: "running",
+			status: ProgressStatus{
+				Done:  1229 * MiB,
+				Total: 4 * GiB,
+				Rate:  85*MiB + 300*KiB,
+				ETA:   33*time.Second + 400*time.Millisecond,
+			},
+			want: "1.2GiB / 4.0GiB (30%) 85.3MiB/s ETA 33s",
+		},
+		{
+			name:   "unknown eta",
+			status: ProgressStatus{Done: 0, Total: 1 * KiB, ETA: -1},
+			want:   "0.0B / 1.0kiB (0%) 0.0B/s ETA -",
+		},
+		{
+			name:   "done",
+			status: ProgressStatus{Done: 1 * KiB, Total: 1 * KiB, Rate: 1 * KiB},
+			want:   "1.0kiB / 1.0kiB (100%) 1.0kiB/s ETA 0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.status.String())
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var updates []ProgressStatus
+	p := &Progress{
+		Total:    10 * MiB,
+		OnUpdate: func(s ProgressStatus) { updates = append(updates, s) },
+		Clock:    clock,
+	}
+
+	p.Add(0)
+	for i := 0; i < 4; i++ {
+		clock.advance(time.Second)
+		p.Add(1 * MiB)
+	}
+	s := p.Status()
+	assert.Equal(t, 4*MiB, s.Done)
+	assert.Equal(t, 4*time.Second, s.Elapsed)
+	assert.Equal(t, 1*MiB, s.Rate)
+	assert.Equal(t, 6*time.Second, s.ETA)
+	assert.Equal(t, 40.0, s.Percent())
+	assert.Len(t, updates, 5)
+
+	// throughput moves towards the new rate
+	clock.advance(time.Second)
+	p.Add(3 * MiB)
+	s = p.Status()
+	assert.Greater(t, uint64(s.Rate), uint64(1*MiB))
+	assert.Less(t, uint64(s.Rate), uint64(3*MiB))
+
+	// completion is always reported
+	clock.advance(time.Second)
+	p.Add(3 * MiB)
+	assert.Len(t, updates, 7)
+	assert.Equal(t, 10*MiB, updates[6].Done)
+	assert.Equal(t, time.Duration(0), updates[6].ETA)
+
+	clock.advance(time.Second)
+	p.Add(1 * MiB)
+	assert.Len(t, updates, 7)
+}
+
+func TestProgress_stall(t *testing.T) {
+	clock := newManualClock()
+	updates := make(chan ProgressStatus, 10)
+	p := &Progress{
+		Total:    10 * MiB,
+		Interval: time.Second,
+		OnUpdate: func(s ProgressStatus) { updates <- s },
+		Clock:    clock,
+	}
+	defer p.Stop()
+
+	p.Add(0)
+	clock.waitTimer(t)
+	clock.advance(time.Second)
+	assert.Equal(t, Bytes(0), (<-updates).Done)
+	clock.waitTimer(t)
+	p.Add(1 * MiB)
+	assert.Equal(t, 1*MiB, p.Status().Rate)
+	assert.Equal(t, 9*time.Second, p.Status().ETA)
+
+	// no bytes arrive for 3 intervals, the updates keep coming with decaying rate
+	last := p.Status()
+	for i := 1; i <= 3; i++ {
+		clock.advance(time.Second)
+		s := <-updates
+		clock.waitTimer(t)
+		assert.Equal(t, 1*MiB, s.Done)
+		assert.Equal(t, time.Duration(i+1)*time.Second, s.Elapsed)
+		assert.Less(t, uint64(s.Rate), uint64(last.Rate))
+		assert.Greater(t, s.ETA, last.ETA)
+		last = s
+	}
+	assert.Equal(t, Bytes(float64(MiB)*math.Exp(-0.6)), last.Rate)
+	assert.Equal(t, last, p.Status())
+
+	// completion is reported at once and ends the timer
+	p.Add(9 * MiB)
+	s := <-updates
+	assert.Equal(t, 10*MiB, s.Done)
+	assert.Equal(t, time.Duration(0), s.ETA)
+	clock.advance(time.Second)
+	select {
+	case s := <-updates:
+		t.Fatalf("unexpected update after completion: %v", s)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestProgress_Stop(t *testing.T) {
+	clock := newManualClock()
+	updates := make(chan ProgressStatus, 10)
+	p := &Progress{Interval: time.Second, OnUpdate: func(s ProgressStatus) { updates <- s }, Clock: clock}
+	p.Add(KiB)
+	clock.waitTimer(t)
+	p.Stop()
+	p.Stop()
+	clock.advance(time.Second)
+	select {
+	case s := <-updates:
+		t.Fatalf("unexpected update after Stop: %v", s)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestProgress_ReaderWriter(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p := &Progress{Total: 10 * KiB, Clock: clock}
+	var dst bytes.Buffer
+	src := strings.NewReader(strings.Repeat("x", 10*1024))
+	_, err := io.Copy(p.Writer(&dst), p.Reader(src))
+	assert.NoError(t, err)
+	assert.Equal(t, 10*1024, dst.Len())
+	assert.Equal(t, 20*KiB, p.Status().Done)
+
+	p = &Progress{Clock: clock}
+	n, err := io.Copy(ioutil.Discard, p.Reader(strings.NewReader("hello")))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, 5*B, p.Status().Done)
+	assert.Equal(t, time.Duration(-1), p.Status().ETA)
+}
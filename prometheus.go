@@ -0,0 +1,138 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BinaryBuckets returns the histogram buckets of Prometheus clients for the binary orders of magnitude,
+// i.e. the sizes below 1kiB, 1MiB, 1GiB and 1TiB. As byte counts are integers, the bounds are
+// 1023, 1048575 and so on, so that 1024 falls in the bucket of 1kiB like Bytes formats it,
+// and the buckets match the ones written by PrometheusWriter.Histogram for NewBinaryHistogram.
+func BinaryBuckets() []float64 {
+	return magnitudeBuckets(binaryMagnitudes[1:])
+}
+
+// DecimalBuckets returns the histogram buckets of Prometheus clients for the decimal orders of magnitude,
+// i.e. the sizes below 1kB, 1MB, 1GB and 1TB, see BinaryBuckets.
+func DecimalBuckets() []float64 {
+	return magnitudeBuckets(decimalMagnitudes[1:])
+}
+
+func magnitudeBuckets(mags []Bytes) []float64 {
+	buckets := make([]float64, len(mags))
+	for i, mag := range mags {
+		buckets[i] = float64(mag - 1)
+	}
+	return buckets
+}
+
+var errPrometheusLabels = errors.New("units: prometheus labels must be name and value pairs")
+
+// PrometheusWriter writes Bytes as metrics in the Prometheus text exposition format.
+// Values are written in bytes, the base unit, so metric names should end with "_bytes".
+//
+// The first error is kept and stops further writes, it is returned by Err.
+type PrometheusWriter struct {
+	w       io.Writer
+	err     error
+	written map[string]bool
+}
+
+// NewPrometheusWriter returns a PrometheusWriter writing to w.
+func NewPrometheusWriter(w io.Writer) *PrometheusWriter {
+	return &PrometheusWriter{w: w, written: map[string]bool{}}
+}
+
+// Err returns the first error that occurred.
+func (p *PrometheusWriter) Err() error {
+	return p.err
+}
+
+// Gauge writes a gauge sample. labels are pairs of label name and value.
+// The HELP and TYPE lines are written only for the first sample of a metric.
+func (p *PrometheusWriter) Gauge(name, help string, b Bytes, labels ...string) {
+	p.header(name, help, "gauge")
+	p.sample(name, labels, "", "", b)
+}
+
+// Counter writes a counter sample, name should end with "_total". labels are pairs of label name and value.
+func (p *PrometheusWriter) Counter(name, help string, b Bytes, labels ...string) {
+	p.header(name, help, "counter")
+	p.sample(name, labels, "", "", b)
+}
+
+// Histogram writes the buckets, sum and count of h. labels are pairs of label name and value.
+// Histogram buckets exclude the upper bound while Prometheus ones include it, so the le labels are
+// the upper bounds minus one, which is exact for integer byte counts. They match BinaryBuckets
+// and DecimalBuckets for NewBinaryHistogram and NewDecimalHistogram.
+func (p *PrometheusWriter) Histogram(name, help string, h *Histogram, labels ...string) {
+	p.header(name, help, "histogram")
+	var cumulative uint64
+	for _, bucket := range h.Buckets() {
+		cumulative += bucket.Count
+		le := "+Inf"
+		if bucket.Upper != 0 {
+			le = strconv.FormatUint(uint64(bucket.Upper-1), 10)
+		}
+		p.sample(name+"_bucket", labels, "le", le, Bytes(cumulative))
+	}
+	p.sample(name+"_sum", labels, "", "", h.Sum())
+	p.sample(name+"_count", labels, "", "", Bytes(cumulative))
+}
+
+func (p *PrometheusWriter) header(name, help, typ string) {
+	if p.err != nil || p.written[name] {
+		return
+	}
+	p.written[name] = true
+	if help != "" {
+		p.printf("# HELP %s %s\n", name, helpEscaper.Replace(help))
+	}
+	p.printf("# TYPE %s %s\n", name, typ)
+}
+
+func (p *PrometheusWriter) sample(name string, labels []string, extraName, extraValue string, b Bytes) {
+	if p.err != nil {
+		return
+	}
+	if len(labels)%2 != 0 {
+		p.err = errPrometheusLabels
+		return
+	}
+	if extraName != "" {
+		labels = append(labels[:len(labels):len(labels)], extraName, extraValue)
+	}
+	var sb strings.Builder
+	sb.WriteString(name)
+	for i := 0; i < len(labels); i += 2 {
+		if i == 0 {
+			sb.WriteByte('{')
+		} else {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(labels[i])
+		sb.WriteString(`="`)
+		sb.WriteString(labelEscaper.Replace(labels[i+1]))
+		sb.WriteByte('"')
+	}
+	if len(labels) > 0 {
+		sb.WriteByte('}')
+	}
+	p.printf("%s %d\n", sb.String(), uint64(b))
+}
+
+func (p *PrometheusWriter) printf(format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
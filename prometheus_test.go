@@ -0,0 +1,92 @@
+package units
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuckets(t *testing.T) {
+	assert.Equal(t, []float64{1023, 1048575, 1073741823, 1099511627775}, BinaryBuckets())
+	assert.Equal(t, []float64{999, 999999, 999999999, 999999999999}, DecimalBuckets())
+}
+
+func TestPrometheusWriter(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrometheusWriter(&buf)
+	p.Gauge("cache_size_bytes", "Size of the cache.", 256*MiB, "cache", "disk")
+	p.Gauge("cache_size_bytes", "Size of the cache.", 1*GiB, "cache", `mem"ory`)
+	p.Counter("transferred_bytes_total", "Bytes transferred.\nIn total.", 5*KiB)
+
+	h := NewBinaryHistogram()
+	h.Observe(KiB - 1)
+	h.Observe(KiB)
+	h.Observe(2 * GiB)
+	p.Histogram("object_size_bytes", "", h, "bucket", "a")
+	assert.NoError(t, p.Err())
+
+	assert.Equal(t, `# HELP cache_size_bytes Size of the cache.
+# TYPE cache_size_bytes gauge
+cache_size_bytes{cache="disk"} 268435456
+cache_size_bytes{cache="mem\"ory"} 1073741824
+# HELP transferred_bytes_total Bytes transferred.\nIn total.
+# TYPE transferred_bytes_total counter
+transferred_bytes_total 5120
+# TYPE object_size_bytes histogram
+object_size_bytes_bucket{bucket="a",le="1023"} 1
+object_size_bytes_bucket{bucket="a",le="1048575"} 2
+object_size_bytes_bucket{bucket="a",le="1073741823"} 2
+object_size_bytes_bucket{bucket="a",le="1099511627775"} 3
+object_size_bytes_bucket{bucket="a",le="+Inf"} 3
+object_size_bytes_sum{bucket="a"} 2147485695
+object_size_bytes_count{bucket="a"} 3
+`, buf.String())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("broken") }
+
+func TestPrometheusWriter_error(t *testing.T) {
+	p := NewPrometheusWriter(&bytes.Buffer{})
+	p.Gauge("size_bytes", "", KiB, "odd")
+	assert.Equal(t, errPrometheusLabels, p.Err())
+
+	p = NewPrometheusWriter(failingWriter{})
+	p.Gauge("size_bytes", "", KiB)
+	p.Gauge("size_bytes", "", KiB)
+	assert.EqualError(t, p.Err(), "broken")
+}
+
+func TestPrometheusWriter_Histogram_buckets(t *testing.T) {
+	for _, tt := range []struct {
+		h       *Histogram
+		buckets []float64
+	}{
+		{NewBinaryHistogram(), BinaryBuckets()},
+		{NewDecimalHistogram(), DecimalBuckets()},
+	} {
+		var buf bytes.Buffer
+		tt.h.Observe(1023)
+		tt.h.Observe(1024)
+		p := NewPrometheusWriter(&buf)
+		p.Histogram("size_bytes", "", tt.h)
+		assert.NoError(t, p.Err())
+		// the le labels are the same as the buckets for clients
+		for _, bucket := range tt.buckets {
+			assert.Contains(t, buf.String(), fmt.Sprintf("size_bytes_bucket{le=\"%.0f\"}", bucket))
+		}
+	}
+
+	var buf bytes.Buffer
+	h := NewBinaryHistogram()
+	h.Observe(KiB)
+	p := NewPrometheusWriter(&buf)
+	p.Histogram("size_bytes", "", h)
+	// 1024 is formatted as 1kiB, so it is in the bucket of 1kiB
+	assert.Contains(t, buf.String(), "size_bytes_bucket{le=\"1023\"} 0\n")
+	assert.Contains(t, buf.String(), "size_bytes_bucket{le=\"1048575\"} 1\n")
+}
@@ -0,0 +1,175 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrQuotaExceeded is matched by QuotaError with errors.Is.
+	ErrQuotaExceeded = errors.New("units: quota exceeded")
+	// ErrReservationDone is returned when a reservation is committed or released twice.
+	ErrReservationDone = errors.New("units: reservation already committed or released")
+)
+
+// QuotaError is returned when a reservation exceeds the hard limit of a Quota.
+type QuotaError struct {
+	Requested Bytes
+	Available Bytes
+	Limit     Bytes
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("units: quota exceeded: requested %f, available %f of %f", e.Requested, e.Available, e.Limit)
+}
+
+// Is reports whether target is ErrQuotaExceeded.
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// Quota accounts the usage of storage against a hard limit and an optional soft limit.
+//
+// Space is first reserved, then either committed to the usage or released.
+// Exceeding the hard limit fails, while exceeding the soft limit is only reported.
+// It is safe for concurrent use.
+type Quota struct {
+	mu       sync.Mutex
+	soft     Bytes
+	hard     Bytes
+	used     Bytes
+	reserved Bytes
+}
+
+// NewQuota returns a Quota with the hard limit, the soft limit is the same as the hard one.
+func NewQuota(hard Bytes) *Quota {
+	return &Quota{soft: hard, hard: hard}
+}
+
+// SetLimits changes the soft and hard limits. Existing usage and reservations are kept even if they exceed.
+func (q *Quota) SetLimits(soft, hard Bytes) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.soft, q.hard = soft, hard
+}
+
+// Reserve reserves n bytes, or returns a *QuotaError if the hard limit would be exceeded.
+func (q *Quota) Reserve(n Bytes) (*Reservation, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if available := q.available(); n > available {
+		return nil, &QuotaError{Requested: n, Available: available, Limit: q.hard}
+	}
+	q.reserved += n
+	return &Reservation{
+		q:        q,
+		n:        n,
+		overSoft: q.used+q.reserved > q.soft,
+	}, nil
+}
+
+// Free gives back n committed bytes, e.g. when files are deleted.
+func (q *Quota) Free(n Bytes) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if n > q.used {
+		return ErrUnderflow
+	}
+	q.used -= n
+	return nil
+}
+
+// Used returns the committed bytes.
+func (q *Quota) Used() Bytes {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.used
+}
+
+// Reserved returns the bytes reserved but not committed or released yet.
+func (q *Quota) Reserved() Bytes {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.reserved
+}
+
+// Remaining returns the bytes can still be reserved.
+func (q *Quota) Remaining() Bytes {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.available()
+}
+
+// OverSoftLimit reports whether the committed and reserved bytes exceed the soft limit.
+func (q *Quota) OverSoftLimit() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.used+q.reserved > q.soft
+}
+
+// String reports the usage like "1.0GiB used, 256.0MiB reserved, 2.8GiB remaining of 4.0GiB".
+func (q *Quota) String() string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return fmt.Sprintf("%f used, %f reserved, %f remaining of %f", q.used, q.reserved, q.available(), q.hard)
+}
+
+func (q *Quota) available() Bytes {
+	if q.used+q.reserved >= q.hard {
+		return 0
+	}
+	return q.hard - q.used - q.reserved
+}
+
+// Reservation is space reserved in a Quota.
+type Reservation struct {
+	q        *Quota
+	n        Bytes
+	overSoft bool
+	done     bool
+}
+
+// Size returns the reserved bytes.
+func (r *Reservation) Size() Bytes {
+	return r.n
+}
+
+// OverSoftLimit reports whether the soft limit was exceeded when the space was reserved.
+func (r *Reservation) OverSoftLimit() bool {
+	return r.overSoft
+}
+
+// Commit adds the reserved bytes to the usage of the quota.
+func (r *Reservation) Commit() error {
+	return r.CommitN(r.n)
+}
+
+// CommitN adds n of the reserved bytes to the usage of the quota and releases the rest,
+// for uploads turning out to be smaller than expected. n must not exceed the reserved bytes.
+func (r *Reservation) CommitN(n Bytes) error {
+	r.q.mu.Lock()
+	defer r.q.mu.Unlock()
+	if r.done {
+		return ErrReservationDone
+	}
+	if n > r.n {
+		return &QuotaError{Requested: n, Available: r.n, Limit: r.q.hard}
+	}
+	r.done = true
+	r.q.reserved -= r.n
+	r.q.used += n
+	return nil
+}
+
+// Release gives back the reserved bytes to the quota.
+func (r *Reservation) Release() error {
+	r.q.mu.Lock()
+	defer r.q.mu.Unlock()
+	if r.done {
+		return ErrReservationDone
+	}
+	r.done = true
+	r.q.reserved -= r.n
+	return nil
+}
@@ -0,0 +1,80 @@
+package units
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuota(t *testing.T) {
+	q := NewQuota(4 * GiB)
+	q.SetLimits(3*GiB, 4*GiB)
+
+	r1, err := q.Reserve(2 * GiB)
+	require.NoError(t, err)
+	assert.False(t, r1.OverSoftLimit())
+	r2, err := q.Reserve(GiB + 512*MiB)
+	require.NoError(t, err)
+	assert.True(t, r2.OverSoftLimit())
+	assert.True(t, q.OverSoftLimit())
+	assert.Equal(t, "0.0B used, 3.5GiB reserved, 512.0MiB remaining of 4.0GiB", q.String())
+
+	_, err = q.Reserve(GiB)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+	assert.EqualError(t, err, "units: quota exceeded: requested 1.0GiB, available 512.0MiB of 4.0GiB")
+	var qerr *QuotaError
+	require.True(t, errors.As(err, &qerr))
+	assert.Equal(t, GiB, qerr.Requested)
+	assert.Equal(t, 512*MiB, qerr.Available)
+
+	assert.NoError(t, r1.Commit())
+	assert.Equal(t, ErrReservationDone, r1.Commit())
+	assert.NoError(t, r2.Release())
+	assert.Equal(t, ErrReservationDone, r2.Release())
+	assert.Equal(t, 2*GiB, q.Used())
+	assert.Equal(t, Bytes(0), q.Reserved())
+	assert.Equal(t, 2*GiB, q.Remaining())
+	assert.False(t, q.OverSoftLimit())
+
+	r3, err := q.Reserve(GiB)
+	require.NoError(t, err)
+	assert.Equal(t, GiB, r3.Size())
+	assert.Error(t, r3.CommitN(2*GiB))
+	assert.NoError(t, r3.CommitN(MiB))
+	assert.Equal(t, 2*GiB+MiB, q.Used())
+
+	assert.NoError(t, q.Free(2*GiB))
+	assert.Equal(t, ErrUnderflow, q.Free(2*MiB))
+	assert.Equal(t, MiB, q.Used())
+
+	// lowering the limits keeps the usage
+	q.SetLimits(0, 0)
+	assert.Equal(t, Bytes(0), q.Remaining())
+	_, err = q.Reserve(1)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+}
+
+func TestQuota_concurrent(t *testing.T) {
+	q := NewQuota(100 * KiB)
+	var wg sync.WaitGroup
+	var committed AtomicBytes
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := q.Reserve(KiB)
+			if err != nil {
+				return
+			}
+			if r.Commit() == nil {
+				committed.Add(KiB)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100*KiB, q.Used())
+	assert.Equal(t, 100*KiB, committed.Load())
+}
@@ -0,0 +1,154 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrOutOfRange is matched by *RangeError.
+	ErrOutOfRange = errors.New("units: out of range")
+	// ErrInvalidRange is returned when the minimum of a range is greater than the maximum.
+	ErrInvalidRange = errors.New("units: invalid range")
+)
+
+// Range is an inclusive interval of sizes, like "part size must be between 5MiB and 5GiB".
+//
+// It implements flag.Value and encoding.TextUnmarshaler, so it can be used as a flag or in configs.
+// The text form is "5MiB..5GiB" where either end can be omitted, a missing Min is zero
+// and a missing Max is unbounded, i.e. the maximum of Bytes.
+type Range struct {
+	Min, Max Bytes
+}
+
+// RangeError is returned when a size is out of a range.
+type RangeError struct {
+	Value Bytes
+	Range Range
+}
+
+func (e *RangeError) Error() string {
+	if e.Value < e.Range.Min {
+		return fmt.Sprintf("units: %f is less than the minimum %f", e.Value, e.Range.Min)
+	}
+	return fmt.Sprintf("units: %f is greater than the maximum %f", e.Value, e.Range.Max)
+}
+
+// Is reports whether target is ErrOutOfRange.
+func (e *RangeError) Is(target error) bool {
+	return target == ErrOutOfRange
+}
+
+// ParseRange parses a range like "5MiB..5GiB", "5MiB.." or "..5GiB", both ends are parsed by Parse.
+// There must be exactly one "..", so a fractional upper end needs a leading zero, like "1GiB..0.5TiB".
+// The returned error is a *ParseError.
+func ParseRange(s string) (Range, error) {
+	i := strings.Index(s, "..")
+	// "5MiB...5GiB" would be 5MiB..0.5GiB, reject it rather than guess
+	if i < 0 || strings.Count(s, "..") > 1 || strings.HasPrefix(strings.TrimSpace(s[i+2:]), ".") {
+		return Range{}, &ParseError{Input: s, Err: ErrSyntax}
+	}
+	r := Range{Max: math.MaxUint64}
+	for _, end := range []struct {
+		text string
+		b    *Bytes
+	}{{s[:i], &r.Min}, {s[i+2:], &r.Max}} {
+		if strings.TrimSpace(end.text) == "" {
+			continue
+		}
+		b, err := Parse(end.text)
+		if err != nil {
+			return Range{}, &ParseError{Input: s, Err: err.(*ParseError).Err}
+		}
+		*end.b = b
+	}
+	if !r.Valid() {
+		return Range{}, &ParseError{Input: s, Err: ErrInvalidRange}
+	}
+	return r, nil
+}
+
+// Valid reports whether r is not empty, i.e. Min is not greater than Max.
+func (r Range) Valid() bool {
+	return r.Min <= r.Max
+}
+
+// Contains reports whether b is in r.
+func (r Range) Contains(b Bytes) bool {
+	return r.Min <= b && b <= r.Max
+}
+
+// Check returns a *RangeError if b is out of r.
+func (r Range) Check(b Bytes) error {
+	if !r.Contains(b) {
+		return &RangeError{Value: b, Range: r}
+	}
+	return nil
+}
+
+// Clamp returns the nearest size to b in r. r must be valid.
+func (r Range) Clamp(b Bytes) Bytes {
+	if b < r.Min {
+		return r.Min
+	}
+	if b > r.Max {
+		return r.Max
+	}
+	return b
+}
+
+// Intersect returns the sizes in both r and o, and whether there is any.
+func (r Range) Intersect(o Range) (Range, bool) {
+	i := r
+	if o.Min > i.Min {
+		i.Min = o.Min
+	}
+	if o.Max < i.Max {
+		i.Max = o.Max
+	}
+	return i, i.Valid()
+}
+
+// String returns r in the form accepted by ParseRange, e.g. "5MiB..5GiB".
+func (r Range) String() string {
+	s := exactString(r.Min) + ".."
+	if r.Max != math.MaxUint64 {
+		s += exactString(r.Max)
+	}
+	return s
+}
+
+// Set implements flag.Value.
+func (r *Range) Set(s string) error {
+	parsed, err := ParseRange(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r Range) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *Range) UnmarshalText(text []byte) error {
+	return r.Set(string(text))
+}
+
+// exactString formats b without losing precision, with the largest unit it is a multiple of.
+func exactString(b Bytes) string {
+	for i := len(binaryMagnitudes) - 1; i > 0 && b != 0; i-- {
+		for _, mag := range []Bytes{binaryMagnitudes[i], decimalMagnitudes[i]} {
+			if b%mag == 0 {
+				return strconv.FormatUint(uint64(b/mag), 10) + string(unitNames[mag])
+			}
+		}
+	}
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
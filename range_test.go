@@ -0,0 +1,129 @@
+package units
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Range
+		wantErr error
+	}{
+		{s: "5MiB..5GiB", want: Range{Min: 5 * MiB, Max: 5 * GiB}},
+		{s: " 5 MiB .. 5 GiB ", want: Range{Min: 5 * MiB, Max: 5 * GiB}},
+		{s: "1.5kB..2M", want: Range{Min: 1500, Max: 2 * MiB}},
+		{s: "5MiB..", want: Range{Min: 5 * MiB, Max: math.MaxUint64}},
+		{s: "..5GiB", want: Range{Max: 5 * GiB}},
+		{s: "..", want: Range{Max: math.MaxUint64}},
+		{s: "4k..4k", want: Range{Min: 4 * KiB, Max: 4 * KiB}},
+		{s: "5MiB", wantErr: ErrSyntax},
+		{s: "5XiB..5GiB", wantErr: ErrSyntax},
+		{s: "5MiB...5GiB", wantErr: ErrSyntax},
+		{s: "5MiB.. .5GiB", wantErr: ErrSyntax},
+		{s: "5MiB....5GiB", wantErr: ErrSyntax},
+		{s: "1..2..3", wantErr: ErrSyntax},
+		{s: "1GiB..0.5TiB", want: Range{Min: GiB, Max: 512 * GiB}},
+		{s: "5MiB..5GiB..", wantErr: ErrSyntax},
+		{s: "..20000000TiB", wantErr: ErrOverflow},
+		{s: "5GiB..5MiB", wantErr: ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		got, err := ParseRange(tt.s)
+		if tt.wantErr != nil {
+			var perr *ParseError
+			require.True(t, errors.As(err, &perr), tt.s)
+			assert.Equal(t, tt.s, perr.Input)
+			assert.True(t, errors.Is(err, tt.wantErr), "%s: %v", tt.s, err)
+			continue
+		}
+		require.NoError(t, err, tt.s)
+		assert.Equal(t, tt.want, got, tt.s)
+	}
+}
+
+func TestRange(t *testing.T) {
+	r := Range{Min: 5 * MiB, Max: 5 * GiB}
+	assert.True(t, r.Valid())
+	assert.False(t, Range{Min: 2, Max: 1}.Valid())
+
+	assert.True(t, r.Contains(5*MiB))
+	assert.True(t, r.Contains(5*GiB))
+	assert.False(t, r.Contains(5*MiB-1))
+	assert.False(t, r.Contains(5*GiB+1))
+
+	assert.Equal(t, 5*MiB, r.Clamp(KiB))
+	assert.Equal(t, GiB, r.Clamp(GiB))
+	assert.Equal(t, 5*GiB, r.Clamp(TiB))
+
+	i, ok := r.Intersect(Range{Min: GiB, Max: 10 * GiB})
+	assert.True(t, ok)
+	assert.Equal(t, Range{Min: GiB, Max: 5 * GiB}, i)
+	_, ok = r.Intersect(Range{Min: 6 * GiB, Max: 10 * GiB})
+	assert.False(t, ok)
+}
+
+func TestRange_Check(t *testing.T) {
+	r := Range{Min: 5 * MiB, Max: 5 * GiB}
+	assert.NoError(t, r.Check(GiB))
+
+	err := r.Check(MiB)
+	assert.EqualError(t, err, "units: 1.0MiB is less than the minimum 5.0MiB")
+	assert.True(t, errors.Is(err, ErrOutOfRange))
+	var rerr *RangeError
+	require.True(t, errors.As(err, &rerr))
+	assert.Equal(t, RangeError{Value: MiB, Range: r}, *rerr)
+
+	assert.EqualError(t, r.Check(6*GiB), "units: 6.0GiB is greater than the maximum 5.0GiB")
+}
+
+func TestRange_String(t *testing.T) {
+	tests := []struct {
+		r    Range
+		want string
+	}{
+		{Range{Min: 5 * MiB, Max: 5 * GiB}, "5MiB..5GiB"},
+		{Range{Min: 1500, Max: 5 * GB}, "1500B..5GB"},
+		{Range{Min: 3 * KB, Max: math.MaxUint64}, "3kB.."},
+		{Range{Max: 1536 * MiB}, "0B..1536MiB"},
+		{Range{Max: 2000 * GB}, "0B..2TB"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.r.String())
+		parsed, err := ParseRange(tt.want)
+		require.NoError(t, err)
+		assert.Equal(t, tt.r, parsed)
+	}
+}
+
+func TestRange_Flag(t *testing.T) {
+	r := Range{Min: MiB, Max: GiB}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Var(&r, "part-size", "")
+	require.NoError(t, fs.Parse([]string{"-part-size", "5MiB..5GiB"}))
+	assert.Equal(t, Range{Min: 5 * MiB, Max: 5 * GiB}, r)
+	assert.Error(t, fs.Parse([]string{"-part-size", "5GiB"}))
+}
+
+func TestRange_Text(t *testing.T) {
+	var config struct {
+		PartSize Range
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"PartSize": "5MiB..5GiB"}`), &config))
+	assert.Equal(t, Range{Min: 5 * MiB, Max: 5 * GiB}, config.PartSize)
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(t, `{"PartSize":"5MiB..5GiB"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"PartSize": "5GiB..5MiB"}`), &config))
+}
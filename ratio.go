@@ -0,0 +1,64 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Ratio returns a/b. It returns NaN if both are zero, and +Inf if only b is zero.
+func Ratio(a, b Bytes) float64 {
+	return float64(a) / float64(b)
+}
+
+// Percent returns a/b in percentage.
+func Percent(a, b Bytes) float64 {
+	return Ratio(a, b) * 100
+}
+
+// Scale returns b multiplied by f, rounded by mode. The product is exact, so Scale(b, 1, mode) is always b.
+// It returns ErrUnderflow if the result is negative, ErrOverflow if it exceeds the maximum of Bytes,
+// and ErrNaN if f is NaN or b is zero and f is infinite.
+func Scale(b Bytes, f float64, mode RoundingMode) (Bytes, error) {
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0) && b == 0:
+		return 0, ErrNaN
+	case math.IsInf(f, 1):
+		return 0, ErrOverflow
+	case math.IsInf(f, -1):
+		return 0, ErrUnderflow
+	}
+	// the product of 64 and 53 significant bits is exact in 128 bits
+	x := new(big.Float).SetPrec(128).SetUint64(uint64(b))
+	x.Mul(x, new(big.Float).SetPrec(128).SetFloat64(f))
+	i := mode.roundBig(x)
+	switch {
+	case i.Sign() < 0:
+		return 0, ErrUnderflow
+	case !i.IsUint64():
+		return 0, ErrOverflow
+	}
+	return Bytes(i.Uint64()), nil
+}
+
+// PartOf is a part of a whole, formatted like "3.2GiB of 10.0GiB (32.0%)".
+type PartOf struct {
+	Part, Whole Bytes
+}
+
+// Of returns b as a part of whole.
+func (b Bytes) Of(whole Bytes) PartOf {
+	return PartOf{Part: b, Whole: whole}
+}
+
+// Format implements fmt.Formatter. The verb and flags apply to both sizes, e.g. "%#.2f",
+// and the percentage is always formatted with one digit after the decimal point.
+// The percentage is omitted if the whole is zero.
+func (p PartOf) Format(f fmt.State, verb rune) {
+	p.Part.Format(f, verb)
+	f.Write([]byte(" of "))
+	p.Whole.Format(f, verb)
+	if p.Whole != 0 {
+		fmt.Fprintf(f, " (%.1f%%)", Percent(p.Part, p.Whole))
+	}
+}
@@ -0,0 +1,62 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatio(t *testing.T) {
+	assert.Equal(t, 0.25, Ratio(KiB, 4*KiB))
+	assert.Equal(t, 4.0, Ratio(4*KiB, KiB))
+	assert.True(t, math.IsInf(Ratio(1, 0), 1))
+	assert.True(t, math.IsNaN(Ratio(0, 0)))
+	assert.Equal(t, 32.0, Percent(32*MiB, 100*MiB))
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		b       Bytes
+		f       float64
+		mode    RoundingMode
+		want    Bytes
+		wantErr error
+	}{
+		{b: GiB, f: 0.5, mode: RoundNearest, want: 512 * MiB},
+		{b: 3, f: 0.5, mode: RoundNearest, want: 2},
+		{b: 3, f: 0.5, mode: RoundNearestEven, want: 2},
+		{b: 5, f: 0.5, mode: RoundNearestEven, want: 2},
+		{b: 3, f: 0.5, mode: RoundDown, want: 1},
+		{b: 3, f: 0.1, mode: RoundUp, want: 1},
+		{b: TiB, f: 1 << 24, mode: RoundNearest, wantErr: ErrOverflow},
+		{b: KiB, f: -1, mode: RoundNearest, wantErr: ErrUnderflow},
+		{b: KiB, f: math.NaN(), mode: RoundNearest, wantErr: ErrNaN},
+		{b: math.MaxUint64, f: 1, mode: RoundNearest, want: math.MaxUint64},
+		{b: 1<<53 + 1, f: 1, mode: RoundNearest, want: 1<<53 + 1},
+		{b: math.MaxUint64, f: 0.5, mode: RoundDown, want: math.MaxUint64 / 2},
+		{b: math.MaxUint64, f: 0.5, mode: RoundUp, want: math.MaxUint64/2 + 1},
+		{b: 1<<53 + 1, f: 3, mode: RoundNearest, want: 3<<53 + 3},
+		{b: math.MaxUint64, f: 1.0000001, mode: RoundNearest, wantErr: ErrOverflow},
+		{b: 7, f: 0.5, mode: RoundNearestEven, want: 4},
+		{b: 1, f: -0.1, mode: RoundNearest, want: 0},
+		{b: 1, f: -0.1, mode: RoundDown, wantErr: ErrUnderflow},
+		{b: 1, f: math.Inf(1), mode: RoundNearest, wantErr: ErrOverflow},
+		{b: 0, f: math.Inf(1), mode: RoundNearest, wantErr: ErrNaN},
+		{b: 1, f: math.Inf(-1), mode: RoundNearest, wantErr: ErrUnderflow},
+	}
+	for _, tt := range tests {
+		got, err := Scale(tt.b, tt.f, tt.mode)
+		assert.Equal(t, tt.wantErr, err, "%d*%v", tt.b, tt.f)
+		assert.Equal(t, tt.want, got, "%d*%v", tt.b, tt.f)
+	}
+}
+
+func TestPartOf_Format(t *testing.T) {
+	used := 3*GiB + 205*MiB
+	assert.Equal(t, "3.2GiB of 10.0GiB (32.0%)", fmt.Sprintf("%f", used.Of(10*GiB)))
+	assert.Equal(t, "3.44GB of 10.74GB (32.0%)", fmt.Sprintf("%#.2f", used.Of(10*GiB)))
+	assert.Equal(t, "3GiB of 10GiB (32.0%)", fmt.Sprintf("%s", used.Of(10*GiB)))
+	assert.Equal(t, "0.0B of 0.0B", fmt.Sprintf("%f", Bytes(0).Of(0)))
+}
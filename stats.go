@@ -0,0 +1,241 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Sum returns the sum of bs, or ErrOverflow if the sum exceeds the maximum of Bytes.
+func Sum(bs []Bytes) (Bytes, error) {
+	var sum Bytes
+	for _, b := range bs {
+		if sum > math.MaxUint64-b {
+			return 0, ErrOverflow
+		}
+		sum += b
+	}
+	return sum, nil
+}
+
+// statsAccuracy is the relative accuracy of the quantiles of Stats.
+const statsAccuracy = 0.01
+
+var (
+	statsGamma    = (1 + statsAccuracy) / (1 - statsAccuracy)
+	statsLogGamma = math.Log(statsGamma)
+)
+
+// Stats accumulates summary statistics of a stream of Bytes.
+//
+// Quantiles are approximated within 1% relative error by counting values in logarithmic buckets,
+// so that the memory used grows with the range of the values rather than the count.
+// Stats from different goroutines can be combined with Merge.
+// The zero value is ready to use, and it is safe for concurrent use.
+type Stats struct {
+	mu       sync.Mutex
+	count    uint64
+	total    Bytes
+	overflow bool
+	// fsum keeps the sum as float for the mean when total overflows
+	fsum    float64
+	min     Bytes
+	max     Bytes
+	zeros   uint64
+	buckets map[int]uint64
+}
+
+// Add records b.
+func (s *Stats) Add(b Bytes) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 || b < s.min {
+		s.min = b
+	}
+	if b > s.max {
+		s.max = b
+	}
+	s.count++
+	if s.total > math.MaxUint64-b {
+		s.overflow = true
+	}
+	s.total += b
+	s.fsum += float64(b)
+	if b == 0 {
+		s.zeros++
+		return
+	}
+	if s.buckets == nil {
+		s.buckets = map[int]uint64{}
+	}
+	s.buckets[statsBucket(b)]++
+}
+
+// Merge adds all the values recorded in o to s.
+func (s *Stats) Merge(o *Stats) {
+	if s == o {
+		return
+	}
+	o.mu.Lock()
+	c := Stats{
+		count:    o.count,
+		total:    o.total,
+		overflow: o.overflow,
+		fsum:     o.fsum,
+		min:      o.min,
+		max:      o.max,
+		zeros:    o.zeros,
+		buckets:  make(map[int]uint64, len(o.buckets)),
+	}
+	for i, n := range o.buckets {
+		c.buckets[i] = n
+	}
+	o.mu.Unlock()
+	if c.count == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 || c.min < s.min {
+		s.min = c.min
+	}
+	if c.max > s.max {
+		s.max = c.max
+	}
+	s.count += c.count
+	if c.overflow || s.total > math.MaxUint64-c.total {
+		s.overflow = true
+	}
+	s.total += c.total
+	s.fsum += c.fsum
+	s.zeros += c.zeros
+	if s.buckets == nil {
+		s.buckets = map[int]uint64{}
+	}
+	for i, n := range c.buckets {
+		s.buckets[i] += n
+	}
+}
+
+// Count returns the number of recorded values.
+func (s *Stats) Count() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Total returns the sum of recorded values, or ErrOverflow if the sum exceeds the maximum of Bytes.
+func (s *Stats) Total() (Bytes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.overflow {
+		return 0, ErrOverflow
+	}
+	return s.total, nil
+}
+
+// Min returns the minimum recorded value, or zero if nothing is recorded.
+func (s *Stats) Min() Bytes {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.min
+}
+
+// Max returns the maximum recorded value.
+func (s *Stats) Max() Bytes {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.max
+}
+
+// Mean returns the arithmetic mean of recorded values, rounded down.
+func (s *Stats) Mean() Bytes {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		return 0
+	}
+	if !s.overflow {
+		return s.total / Bytes(s.count)
+	}
+	return clampBytes(s.fsum / float64(s.count))
+}
+
+// Median returns the approximate median of recorded values.
+func (s *Stats) Median() Bytes {
+	return s.Quantile(0.5)
+}
+
+// Quantile returns the approximate q-quantile of recorded values, q is in range [0, 1].
+func (s *Stats) Quantile(q float64) Bytes {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		return 0
+	}
+	switch {
+	case q <= 0:
+		return s.min
+	case q >= 1:
+		return s.max
+	}
+
+	rank := uint64(q * float64(s.count-1))
+	if rank < s.zeros {
+		return 0
+	}
+	seen := s.zeros
+	for _, i := range sortedKeys(s.buckets) {
+		seen += s.buckets[i]
+		if rank < seen {
+			v := clampBytes(2 * math.Pow(statsGamma, float64(i)) / (statsGamma + 1))
+			if v < s.min {
+				return s.min
+			}
+			if v > s.max {
+				return s.max
+			}
+			return v
+		}
+	}
+	return s.max
+}
+
+// String summarizes s like "count=3 total=3.0kiB min=1.0kiB max=1.0kiB mean=1.0kiB p50=1.0kiB p90=1.0kiB p99=1.0kiB".
+func (s *Stats) String() string {
+	total, err := s.Total()
+	totalStr := fmt.Sprintf("%f", total)
+	if err != nil {
+		totalStr = "overflow"
+	}
+	return fmt.Sprintf("count=%d total=%s min=%f max=%f mean=%f p50=%f p90=%f p99=%f",
+		s.Count(), totalStr, s.Min(), s.Max(), s.Mean(), s.Quantile(0.5), s.Quantile(0.9), s.Quantile(0.99))
+}
+
+// statsBucket returns the index of the bucket covering (gamma^(i-1), gamma^i], b must be positive.
+func statsBucket(b Bytes) int {
+	return int(math.Ceil(math.Log(float64(b)) / statsLogGamma))
+}
+
+// clampBytes converts f to Bytes, saturating at the bounds, since the conversion of an out of range float is undefined.
+func clampBytes(f float64) Bytes {
+	switch {
+	case f <= 0 || math.IsNaN(f):
+		return 0
+	case f >= math.MaxUint64:
+		return math.MaxUint64
+	default:
+		return Bytes(f)
+	}
+}
+
+func sortedKeys(m map[int]uint64) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package units
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name    string
+		bs      []Bytes
+		want    Bytes
+		wantErr error
+	}{
+		{name: "empty", bs: nil, want: 0},
+		{name: "sum", bs: []Bytes{KiB, MiB, 1}, want: MiB + KiB + 1},
+		{name: "max", bs: []Bytes{math.MaxUint64 - 1, 1}, want: math.MaxUint64},
+		{name: "overflow", bs: []Bytes{math.MaxUint64, 1}, wantErr: ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sum(tt.bs)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	var s Stats
+	assert.Equal(t, Bytes(0), s.Median())
+	assert.Equal(t, Bytes(0), s.Mean())
+
+	r := rand.New(rand.NewSource(1))
+	values := make([]Bytes, 10000)
+	for i := range values {
+		values[i] = Bytes(r.Int63n(int64(GiB)))
+		s.Add(values[i])
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	sum, _ := Sum(values)
+
+	assert.Equal(t, uint64(len(values)), s.Count())
+	total, err := s.Total()
+	assert.NoError(t, err)
+	assert.Equal(t, sum, total)
+	assert.Equal(t, values[0], s.Min())
+	assert.Equal(t, values[len(values)-1], s.Max())
+	assert.Equal(t, sum/Bytes(len(values)), s.Mean())
+	for _, q := range []float64{0.1, 0.5, 0.9, 0.99} {
+		want := float64(values[int(q*float64(len(values)-1))])
+		assert.InEpsilon(t, want, float64(s.Quantile(q)), statsAccuracy, "q=%v", q)
+	}
+	assert.Equal(t, s.Min(), s.Quantile(0))
+	assert.Equal(t, s.Max(), s.Quantile(1))
+}
+
+func TestStats_zero(t *testing.T) {
+	var s Stats
+	for _, b := range []Bytes{0, 0, 0, KiB} {
+		s.Add(b)
+	}
+	assert.Equal(t, Bytes(0), s.Median())
+	assert.Equal(t, KiB, s.Quantile(1))
+	assert.Equal(t, Bytes(256), s.Mean())
+}
+
+func TestStats_overflow(t *testing.T) {
+	var s Stats
+	s.Add(math.MaxUint64)
+	s.Add(math.MaxUint64)
+	_, err := s.Total()
+	assert.Equal(t, ErrOverflow, err)
+	assert.Equal(t, "count=2 total=overflow min=16777216.0TiB max=16777216.0TiB mean=16777216.0TiB p50=16777216.0TiB p90=16777216.0TiB p99=16777216.0TiB", s.String())
+}
+
+func TestStats_Merge(t *testing.T) {
+	var all Stats
+	parts := make([]Stats, 4)
+	var wg sync.WaitGroup
+	for i := range parts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				parts[i].Add(Bytes(i*1000+j) * KiB)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i := range parts {
+		all.Merge(&parts[i])
+	}
+	all.Merge(&all)
+	all.Merge(&Stats{})
+
+	assert.Equal(t, uint64(4000), all.Count())
+	assert.Equal(t, Bytes(0), all.Min())
+	assert.Equal(t, 3999*KiB, all.Max())
+	assert.InEpsilon(t, float64(1999*KiB), float64(all.Median()), statsAccuracy)
+	assert.Equal(t, "count=4000 total=7.6GiB min=0.0B max=3.9MiB mean=2.0MiB p50=1.9MiB p90=3.5MiB p99=3.8MiB", all.String())
+}
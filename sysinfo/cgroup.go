@@ -0,0 +1,157 @@
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ylin610/units"
+)
+
+// Unlimited is the value of a cgroup limit which is set to "max".
+const Unlimited = units.Bytes(math.MaxUint64)
+
+// CgroupMemory holds the memory accounting of a cgroup v2.
+type CgroupMemory struct {
+	// Max is the hard limit from memory.max, Unlimited if not set.
+	Max units.Bytes
+	// Current is the usage from memory.current.
+	Current units.Bytes
+	Stat    *MemoryStat
+}
+
+// Remaining returns the bytes can still be used before reaching the limit.
+func (m *CgroupMemory) Remaining() units.Bytes {
+	if m.Current >= m.Max {
+		return 0
+	}
+	return m.Max - m.Current
+}
+
+// MemoryStat holds the common fields of memory.stat of a cgroup v2.
+type MemoryStat struct {
+	Anon              units.Bytes
+	File              units.Bytes
+	Kernel            units.Bytes
+	KernelStack       units.Bytes
+	Pagetables        units.Bytes
+	Sock              units.Bytes
+	Shmem             units.Bytes
+	FileMapped        units.Bytes
+	FileDirty         units.Bytes
+	FileWriteback     units.Bytes
+	ActiveAnon        units.Bytes
+	InactiveAnon      units.Bytes
+	ActiveFile        units.Bytes
+	InactiveFile      units.Bytes
+	Unevictable       units.Bytes
+	Slab              units.Bytes
+	SlabReclaimable   units.Bytes
+	SlabUnreclaimable units.Bytes
+
+	// Fields holds all the fields as they are, including event counters like "pgfault".
+	Fields map[string]uint64
+}
+
+// ReadCgroupMemory reads memory.max, memory.current and memory.stat in the cgroup directory dir,
+// e.g. "/sys/fs/cgroup".
+func ReadCgroupMemory(dir string) (*CgroupMemory, error) {
+	var m CgroupMemory
+	var err error
+	if m.Max, err = readCgroupFile(filepath.Join(dir, "memory.max")); err != nil {
+		return nil, err
+	}
+	if m.Current, err = readCgroupFile(filepath.Join(dir, "memory.current")); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(dir, "memory.stat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if m.Stat, err = ParseMemoryStat(f); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name(), err)
+	}
+	return &m, nil
+}
+
+func readCgroupFile(name string) (units.Bytes, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return 0, err
+	}
+	b, err := ParseCgroupValue(data)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return b, nil
+}
+
+// ParseCgroupValue parses the content of single value files like memory.max, memory.high and memory.current.
+// "max" is parsed to Unlimited.
+func ParseCgroupValue(data []byte) (units.Bytes, error) {
+	s := string(bytes.TrimSpace(data))
+	if s == "max" {
+		return Unlimited, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("sysinfo: cgroup value: %w", err)
+	}
+	return units.Bytes(n), nil
+}
+
+// ParseMemoryStat parses the content of memory.stat of a cgroup v2.
+func ParseMemoryStat(r io.Reader) (*MemoryStat, error) {
+	stat := &MemoryStat{Fields: map[string]uint64{}}
+	known := map[string]*units.Bytes{
+		"anon":               &stat.Anon,
+		"file":               &stat.File,
+		"kernel":             &stat.Kernel,
+		"kernel_stack":       &stat.KernelStack,
+		"pagetables":         &stat.Pagetables,
+		"sock":               &stat.Sock,
+		"shmem":              &stat.Shmem,
+		"file_mapped":        &stat.FileMapped,
+		"file_dirty":         &stat.FileDirty,
+		"file_writeback":     &stat.FileWriteback,
+		"active_anon":        &stat.ActiveAnon,
+		"inactive_anon":      &stat.InactiveAnon,
+		"active_file":        &stat.ActiveFile,
+		"inactive_file":      &stat.InactiveFile,
+		"unevictable":        &stat.Unevictable,
+		"slab":               &stat.Slab,
+		"slab_reclaimable":   &stat.SlabReclaimable,
+		"slab_unreclaimable": &stat.SlabUnreclaimable,
+	}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("sysinfo: memory.stat line %d: malformed line %q", line, scanner.Text())
+		}
+		n, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("sysinfo: memory.stat line %d: %w", line, err)
+		}
+		stat.Fields[fields[0]] = n
+		if p, ok := known[fields[0]]; ok {
+			*p = units.Bytes(n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return stat, nil
+}
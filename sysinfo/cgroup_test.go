@@ -0,0 +1,55 @@
+package sysinfo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ylin610/units"
+)
+
+func TestParseCgroupValue(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    units.Bytes
+		wantErr bool
+	}{
+		{data: "max\n", want: Unlimited},
+		{data: "536870912\n", want: 512 * units.MiB},
+		{data: "0", want: 0},
+		{data: "", wantErr: true},
+		{data: "-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCgroupValue([]byte(tt.data))
+		assert.Equal(t, tt.wantErr, err != nil, tt.data)
+		assert.Equal(t, tt.want, got, tt.data)
+	}
+}
+
+func TestReadCgroupMemory(t *testing.T) {
+	m, err := ReadCgroupMemory("testdata/cgroup")
+	require.NoError(t, err)
+	assert.Equal(t, 512*units.MiB, m.Max)
+	assert.Equal(t, units.Bytes(123456789), m.Current)
+	assert.Equal(t, m.Max-m.Current, m.Remaining())
+	assert.Equal(t, units.Bytes(52494336), m.Stat.Anon)
+	assert.Equal(t, units.Bytes(3704600), m.Stat.Slab)
+	assert.Equal(t, uint64(64123), m.Stat.Fields["pgfault"])
+
+	m, err = ReadCgroupMemory("testdata/cgroup-unlimited")
+	require.NoError(t, err)
+	assert.Equal(t, Unlimited, m.Max)
+	assert.Equal(t, Unlimited-4*units.KiB, m.Remaining())
+
+	_, err = ReadCgroupMemory("testdata/missing")
+	assert.Error(t, err)
+}
+
+func TestParseMemoryStat_error(t *testing.T) {
+	for _, content := range []string{"anon", "anon 1 2", "anon x"} {
+		_, err := ParseMemoryStat(strings.NewReader(content))
+		assert.Error(t, err, content)
+	}
+}
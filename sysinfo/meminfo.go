@@ -0,0 +1,126 @@
+// Package sysinfo parses memory and storage information of Linux into [units.Bytes].
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ylin610/units"
+)
+
+// MemInfo holds the common fields of /proc/meminfo.
+//
+// The kernel labels the values as "kB" but they are actually in kiB, they are converted accordingly.
+type MemInfo struct {
+	MemTotal     units.Bytes
+	MemFree      units.Bytes
+	MemAvailable units.Bytes
+	Buffers      units.Bytes
+	Cached       units.Bytes
+	SwapCached   units.Bytes
+	Active       units.Bytes
+	Inactive     units.Bytes
+	SwapTotal    units.Bytes
+	SwapFree     units.Bytes
+	Dirty        units.Bytes
+	Writeback    units.Bytes
+	AnonPages    units.Bytes
+	Mapped       units.Bytes
+	Shmem        units.Bytes
+	Slab         units.Bytes
+	SReclaimable units.Bytes
+	SUnreclaim   units.Bytes
+	CommitLimit  units.Bytes
+	CommittedAS  units.Bytes
+	Hugepagesize units.Bytes
+
+	// Fields holds all the fields measured in bytes, keyed by the name in the file.
+	Fields map[string]units.Bytes
+	// Counts holds all the fields without a unit, like "HugePages_Total".
+	Counts map[string]uint64
+}
+
+// ReadMemInfo reads and parses /proc/meminfo.
+func ReadMemInfo() (*MemInfo, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseMemInfo(f)
+}
+
+// ParseMemInfo parses the content of /proc/meminfo.
+func ParseMemInfo(r io.Reader) (*MemInfo, error) {
+	info := &MemInfo{
+		Fields: map[string]units.Bytes{},
+		Counts: map[string]uint64{},
+	}
+	known := map[string]*units.Bytes{
+		"MemTotal":     &info.MemTotal,
+		"MemFree":      &info.MemFree,
+		"MemAvailable": &info.MemAvailable,
+		"Buffers":      &info.Buffers,
+		"Cached":       &info.Cached,
+		"SwapCached":   &info.SwapCached,
+		"Active":       &info.Active,
+		"Inactive":     &info.Inactive,
+		"SwapTotal":    &info.SwapTotal,
+		"SwapFree":     &info.SwapFree,
+		"Dirty":        &info.Dirty,
+		"Writeback":    &info.Writeback,
+		"AnonPages":    &info.AnonPages,
+		"Mapped":       &info.Mapped,
+		"Shmem":        &info.Shmem,
+		"Slab":         &info.Slab,
+		"SReclaimable": &info.SReclaimable,
+		"SUnreclaim":   &info.SUnreclaim,
+		"CommitLimit":  &info.CommitLimit,
+		"Committed_AS": &info.CommittedAS,
+		"Hugepagesize": &info.Hugepagesize,
+	}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		i := strings.IndexByte(text, ':')
+		if i < 0 {
+			return nil, fmt.Errorf("sysinfo: meminfo line %d: missing ':'", line)
+		}
+		name, fields := text[:i], strings.Fields(text[i+1:])
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("sysinfo: meminfo line %d: malformed value %q", line, text[i+1:])
+		}
+		n, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("sysinfo: meminfo line %d: %w", line, err)
+		}
+		if len(fields) == 1 {
+			info.Counts[name] = n
+			continue
+		}
+		if fields[1] != "kB" {
+			return nil, fmt.Errorf("sysinfo: meminfo line %d: unknown unit %q", line, fields[1])
+		}
+		if n > math.MaxUint64/uint64(units.KiB) {
+			return nil, fmt.Errorf("sysinfo: meminfo line %d: %w", line, units.ErrOverflow)
+		}
+		b := units.Bytes(n) * units.KiB
+		info.Fields[name] = b
+		if p, ok := known[name]; ok {
+			*p = b
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
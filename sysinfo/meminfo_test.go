@@ -0,0 +1,42 @@
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ylin610/units"
+)
+
+func TestParseMemInfo(t *testing.T) {
+	f, err := os.Open("testdata/meminfo")
+	require.NoError(t, err)
+	defer f.Close()
+
+	info, err := ParseMemInfo(f)
+	require.NoError(t, err)
+	assert.Equal(t, 6158152*units.KiB, info.MemTotal)
+	assert.Equal(t, 4889964*units.KiB, info.MemFree)
+	assert.Equal(t, 5680812*units.KiB, info.MemAvailable)
+	assert.Equal(t, 338264*units.KiB, info.CommittedAS)
+	assert.Equal(t, 2*units.MiB, info.Hugepagesize)
+	assert.Equal(t, 12*units.KiB, info.Fields["Active(anon)"])
+	assert.Equal(t, uint64(0), info.Counts["HugePages_Total"])
+	assert.Equal(t, "5.4GiB", fmt.Sprintf("%f", info.MemAvailable))
+}
+
+func TestParseMemInfo_error(t *testing.T) {
+	for _, content := range []string{
+		"MemTotal 1 kB",
+		"MemTotal: kB",
+		"MemTotal: x kB",
+		"MemTotal: 1 MB",
+		"MemTotal: 18446744073709551615 kB",
+	} {
+		_, err := ParseMemInfo(strings.NewReader(content))
+		assert.Error(t, err, content)
+	}
+}
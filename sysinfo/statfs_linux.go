@@ -0,0 +1,41 @@
+//go:build linux
+// +build linux
+
+package sysinfo
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/ylin610/units"
+)
+
+// DiskUsage returns the usage of the filesystem containing path.
+func DiskUsage(path string) (Usage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Usage{}, &os.PathError{Op: "statfs", Path: path, Err: err}
+	}
+	u := usageFromStatfs(&st)
+	u.Path = path
+	return u, nil
+}
+
+func usageFromStatfs(st *syscall.Statfs_t) Usage {
+	// block counts are in the unit of fragment size, Bsize is only the preferred I/O size
+	size := units.Bytes(st.Frsize)
+	if size == 0 {
+		size = units.Bytes(st.Bsize)
+	}
+	u := Usage{
+		Total:      units.Bytes(st.Blocks) * size,
+		Free:       units.Bytes(st.Bfree) * size,
+		Available:  units.Bytes(st.Bavail) * size,
+		Inodes:     st.Files,
+		InodesFree: st.Ffree,
+	}
+	if u.Free < u.Total {
+		u.Used = u.Total - u.Free
+	}
+	return u
+}
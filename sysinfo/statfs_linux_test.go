@@ -0,0 +1,49 @@
+//go:build linux
+// +build linux
+
+package sysinfo
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ylin610/units"
+)
+
+func TestDiskUsage(t *testing.T) {
+	u, err := DiskUsage(".")
+	require.NoError(t, err)
+	assert.Equal(t, ".", u.Path)
+	assert.NotZero(t, u.Total)
+	assert.Equal(t, u.Total, u.Used+u.Free)
+	assert.LessOrEqual(t, uint64(u.Available), uint64(u.Free))
+
+	_, err = DiskUsage("testdata/missing")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_usageFromStatfs(t *testing.T) {
+	st := &syscall.Statfs_t{
+		Bsize:  64 * 1024,
+		Frsize: 4096,
+		Blocks: 1024,
+		Bfree:  512,
+		Bavail: 256,
+		Files:  100,
+		Ffree:  40,
+	}
+	assert.Equal(t, Usage{
+		Total:      4 * units.MiB,
+		Free:       2 * units.MiB,
+		Available:  1 * units.MiB,
+		Used:       2 * units.MiB,
+		Inodes:     100,
+		InodesFree: 40,
+	}, usageFromStatfs(st))
+
+	st.Frsize = 0
+	assert.Equal(t, 64*units.MiB, usageFromStatfs(st).Total)
+}
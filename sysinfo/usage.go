@@ -0,0 +1,75 @@
+package sysinfo
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ylin610/units"
+)
+
+// Usage is the space and inode usage of a filesystem.
+type Usage struct {
+	// Path is the path the usage is queried with.
+	Path string
+	// Total is the size of the filesystem.
+	Total units.Bytes
+	// Free is the free space, including the space reserved for the root user.
+	Free units.Bytes
+	// Available is the free space available to unprivileged users.
+	Available units.Bytes
+	// Used is the size minus the free space.
+	Used units.Bytes
+
+	Inodes     uint64
+	InodesFree uint64
+}
+
+// UsedPercent returns the used percentage as df does, where the reserved space is excluded from the size.
+func (u Usage) UsedPercent() float64 {
+	size := u.Used + u.Available
+	if size == 0 {
+		return 0
+	}
+	return float64(u.Used) / float64(size) * 100
+}
+
+// InodesUsed returns the number of used inodes.
+func (u Usage) InodesUsed() uint64 {
+	if u.InodesFree > u.Inodes {
+		return 0
+	}
+	return u.Inodes - u.InodesFree
+}
+
+// WriteUsageTable writes the usages as a table, one row per usage labeled by its Path.
+// Sizes are formatted with verb 'f' and the percentage is rounded up.
+func WriteUsageTable(w io.Writer, usages ...Usage) error {
+	header := []string{"Size", "Used", "Avail", "Use%", "Path"}
+	rows := [][]string{header}
+	widths := make([]int, len(header))
+	for _, u := range usages {
+		rows = append(rows, []string{
+			fmt.Sprintf("%f", u.Total),
+			fmt.Sprintf("%f", u.Used),
+			fmt.Sprintf("%f", u.Available),
+			fmt.Sprintf("%.0f%%", math.Ceil(u.UsedPercent())),
+			u.Path,
+		})
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	for _, row := range rows {
+		// numbers are right-aligned, the path is left as is
+		if _, err := fmt.Fprintf(w, "%*s %*s %*s %*s %s\n",
+			widths[0], row[0], widths[1], row[1], widths[2], row[2], widths[3], row[3], row[4]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
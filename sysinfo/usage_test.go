@@ -0,0 +1,37 @@
+package sysinfo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ylin610/units"
+)
+
+func TestUsage(t *testing.T) {
+	u := Usage{
+		Total:      100 * units.GiB,
+		Free:       30 * units.GiB,
+		Available:  20 * units.GiB,
+		Used:       70 * units.GiB,
+		Inodes:     1000,
+		InodesFree: 400,
+	}
+	assert.InDelta(t, 77.78, u.UsedPercent(), 0.01)
+	assert.Equal(t, uint64(600), u.InodesUsed())
+	assert.Equal(t, 0.0, Usage{}.UsedPercent())
+	assert.Equal(t, uint64(0), Usage{InodesFree: 1}.InodesUsed())
+}
+
+func TestWriteUsageTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteUsageTable(&buf,
+		Usage{Path: "/", Total: 100 * units.GiB, Used: 70 * units.GiB, Available: 20 * units.GiB},
+		Usage{Path: "/boot", Total: 512 * units.MiB, Used: 100 * units.MiB, Available: 412 * units.MiB},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		"    Size     Used    Avail Use% Path\n"+
+		"100.0GiB  70.0GiB  20.0GiB  78% /\n"+
+		"512.0MiB 100.0MiB 412.0MiB  20% /boot\n", buf.String())
+}
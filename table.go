@@ -0,0 +1,209 @@
+package units
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Table renders rows of labeled sizes as plain text, Markdown or CSV.
+//
+// Sizes are formatted like verb 'f'. In plain text, numbers are aligned on the decimal point
+// and units are right-aligned.
+type Table struct {
+	// Headers are the headers of the label column and the size columns, no header row is written if empty.
+	Headers []string
+	Rows    []TableRow
+	// Precision is the number of digits after the decimal point like in FormatCommon,
+	// zero means integers.
+	Precision int
+	// Decimal uses decimal units instead of binary ones.
+	Decimal bool
+	// CommonUnit formats all sizes in a column with the same unit, picked by Policy.
+	CommonUnit bool
+	// Policy picks the common unit of a column, default is UnitByMax.
+	Policy UnitPolicy
+}
+
+// TableRow is a row of Table.
+type TableRow struct {
+	Label string
+	Sizes []Bytes
+}
+
+// AddRow appends a row.
+func (t *Table) AddRow(label string, sizes ...Bytes) {
+	t.Rows = append(t.Rows, TableRow{Label: label, Sizes: sizes})
+}
+
+// tableCell is a formatted size split into the number and the unit.
+type tableCell struct {
+	number, unit string
+}
+
+func (c tableCell) String() string {
+	return c.number + c.unit
+}
+
+// cells formats all the sizes, the result is indexed by row and then column.
+func (t *Table) cells() [][]tableCell {
+	columns := 0
+	for _, row := range t.Rows {
+		if len(row.Sizes) > columns {
+			columns = len(row.Sizes)
+		}
+	}
+	mags := make([]Bytes, columns)
+	if t.CommonUnit {
+		for i := range mags {
+			var column []Bytes
+			for _, row := range t.Rows {
+				if i < len(row.Sizes) {
+					column = append(column, row.Sizes[i])
+				}
+			}
+			mags[i] = CommonMagnitude(column, t.Decimal, t.Policy)
+		}
+	}
+
+	cells := make([][]tableCell, len(t.Rows))
+	for r, row := range t.Rows {
+		cells[r] = make([]tableCell, len(row.Sizes))
+		for c, b := range row.Sizes {
+			mag := mags[c]
+			if mag == 0 {
+				mag = t.magnitude(b)
+			}
+			cells[r][c].number, cells[r][c].unit = b.formatIn(mag, t.Precision)
+		}
+	}
+	return cells
+}
+
+func (t *Table) magnitude(b Bytes) Bytes {
+	if t.Decimal {
+		return b.decimalMagnitude()
+	}
+	return b.magnitude()
+}
+
+// WriteText writes t as plain text columns separated by two spaces.
+func (t *Table) WriteText(w io.Writer) error {
+	cells := t.cells()
+	columns := len(t.Headers) - 1
+	for _, row := range cells {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	labelWidth := 0
+	numberWidths, unitWidths := make([]int, columns), make([]int, columns)
+	if len(t.Headers) > 0 {
+		labelWidth = len(t.Headers[0])
+	}
+	for r, row := range cells {
+		labelWidth = maxInt(labelWidth, len(t.Rows[r].Label))
+		for c, cell := range row {
+			numberWidths[c] = maxInt(numberWidths[c], len(cell.number))
+			unitWidths[c] = maxInt(unitWidths[c], len(cell.unit))
+		}
+	}
+	// a header wider than the cells widens the numbers
+	for c := 0; c < columns && c+1 < len(t.Headers); c++ {
+		if extra := len(t.Headers[c+1]) - numberWidths[c] - unitWidths[c]; extra > 0 {
+			numberWidths[c] += extra
+		}
+	}
+
+	var sb strings.Builder
+	writeLine := func(label string, values []string) {
+		line := fmt.Sprintf("%-*s", labelWidth, label)
+		for c := 0; c < columns; c++ {
+			value := ""
+			if c < len(values) {
+				value = values[c]
+			}
+			line += fmt.Sprintf("  %*s", numberWidths[c]+unitWidths[c], value)
+		}
+		sb.WriteString(strings.TrimRight(line, " "))
+		sb.WriteByte('\n')
+	}
+	if len(t.Headers) > 0 {
+		writeLine(t.Headers[0], t.Headers[1:])
+	}
+	for r, row := range cells {
+		values := make([]string, len(row))
+		for c, cell := range row {
+			values[c] = fmt.Sprintf("%*s%*s", numberWidths[c], cell.number, unitWidths[c], cell.unit)
+		}
+		writeLine(t.Rows[r].Label, values)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteMarkdown writes t as a Markdown table with the size columns right-aligned.
+func (t *Table) WriteMarkdown(w io.Writer) error {
+	cells := t.cells()
+	columns := len(t.Headers) - 1
+	for _, row := range cells {
+		columns = maxInt(columns, len(row))
+	}
+
+	var sb strings.Builder
+	writeRow := func(values []string) {
+		sb.WriteString("|")
+		for c := 0; c <= columns; c++ {
+			value := ""
+			if c < len(values) {
+				value = strings.ReplaceAll(values[c], "|", `\|`)
+			}
+			sb.WriteString(" " + value + " |")
+		}
+		sb.WriteByte('\n')
+	}
+	headers := t.Headers
+	if len(headers) == 0 {
+		headers = []string{""}
+	}
+	writeRow(headers)
+	sb.WriteString("| --- |")
+	for c := 0; c < columns; c++ {
+		sb.WriteString(" ---: |")
+	}
+	sb.WriteByte('\n')
+	for r, row := range cells {
+		values := []string{t.Rows[r].Label}
+		for _, cell := range row {
+			values = append(values, cell.String())
+		}
+		writeRow(values)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteCSV writes t as CSV, sizes are written with the units.
+func (t *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if len(t.Headers) > 0 {
+		cw.Write(t.Headers)
+	}
+	for r, row := range t.cells() {
+		record := []string{t.Rows[r].Label}
+		for _, cell := range row {
+			record = append(record, cell.String())
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
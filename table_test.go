@@ -0,0 +1,80 @@
+package units
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTable() *Table {
+	t := &Table{Headers: []string{"Name", "Size", "Allocated"}, Precision: 1}
+	t.AddRow("cache", 900*MiB, GiB)
+	t.AddRow("logs", 12, 4*KiB)
+	t.AddRow("database", 12*GiB+512*MiB, 13*GiB)
+	return t
+}
+
+func TestTable_WriteText(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testTable().WriteText(&buf))
+	assert.Equal(t, ""+
+		"Name          Size  Allocated\n"+
+		"cache     900.0MiB     1.0GiB\n"+
+		"logs       12.0  B     4.0kiB\n"+
+		"database   12.5GiB    13.0GiB\n", buf.String())
+
+	table := testTable()
+	table.CommonUnit = true
+	table.Precision = 2
+	buf.Reset()
+	assert.NoError(t, table.WriteText(&buf))
+	assert.Equal(t, ""+
+		"Name          Size  Allocated\n"+
+		"cache      0.88GiB    1.00GiB\n"+
+		"logs       0.00GiB    0.00GiB\n"+
+		"database  12.50GiB   13.00GiB\n", buf.String())
+
+	table = testTable()
+	table.CommonUnit = true
+	table.Policy = UnitByMedian
+	buf.Reset()
+	assert.NoError(t, table.WriteText(&buf))
+	assert.Equal(t, ""+
+		"Name            Size  Allocated\n"+
+		"cache       900.0MiB     1.0GiB\n"+
+		"logs          0.0MiB     0.0GiB\n"+
+		"database  12800.0MiB    13.0GiB\n", buf.String())
+
+	table = &Table{Decimal: true}
+	table.AddRow("a", 1500*KB)
+	table.AddRow("bb", 20, 3)
+	buf.Reset()
+	assert.NoError(t, table.WriteText(&buf))
+	assert.Equal(t, ""+
+		"a    2MB\n"+
+		"bb  20 B  3B\n", buf.String())
+}
+
+func TestTable_WriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testTable().WriteMarkdown(&buf))
+	assert.Equal(t, ""+
+		"| Name | Size | Allocated |\n"+
+		"| --- | ---: | ---: |\n"+
+		"| cache | 900.0MiB | 1.0GiB |\n"+
+		"| logs | 12.0B | 4.0kiB |\n"+
+		"| database | 12.5GiB | 13.0GiB |\n", buf.String())
+}
+
+func TestTable_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	table := testTable()
+	table.Rows[0].Label = "cache, hot"
+	assert.NoError(t, table.WriteCSV(&buf))
+	assert.Equal(t, ""+
+		"Name,Size,Allocated\n"+
+		"\"cache, hot\",900.0MiB,1.0GiB\n"+
+		"logs,12.0B,4.0kiB\n"+
+		"database,12.5GiB,13.0GiB\n", buf.String())
+}
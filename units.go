@@ -43,6 +43,12 @@ var (
 type Bytes uint64
 
 func (b Bytes) Format(f fmt.State, verb rune) {
+	b.format(f, verb, 0, len(binaryMagnitudes)-1)
+}
+
+// format implements Format, but the unit chosen by verbs 's', 'v' and 'f' is kept
+// in range [minLevel, maxLevel], which are indexes of the magnitude tables.
+func (b Bytes) format(f fmt.State, verb rune, minLevel, maxLevel int) {
 	width := 0
 	if w, ok := f.Width(); ok {
 		width = w
@@ -59,6 +65,7 @@ func (b Bytes) Format(f fmt.State, verb rune) {
 		} else {
 			mag = b.magnitude()
 		}
+		mag = clampMagnitude(mag, f.Flag('#'), minLevel, maxLevel)
 	case 'k':
 		mag = magnitudes[f.Flag('#')][1]
 	case 'm':
@@ -146,49 +153,66 @@ func (b Bytes) decimalMagnitude() Bytes {
 // Ceil returns the least value greater than or equal to b,
 // and is multiple of binary order of magnitude of b.
 func (b Bytes) Ceil() Bytes {
-	mag := b.magnitude()
-	return (b + mag - 1) & ^(mag - 1)
+	return b.CeilBy(b.magnitude())
 }
 
 // DecimalCeil returns the least value greater than or equal to b,
 // and is multiple of decimal order of magnitude of b.
 func (b Bytes) DecimalCeil() Bytes {
-	mag := b.decimalMagnitude()
-	switch mod := b % mag; mod {
-	case 0:
-		return b
-	default:
-		return b + mag - mod
-	}
+	return b.CeilBy(b.decimalMagnitude())
 }
 
 // Floor returns the greatest value less than or equal to b,
 // and is multiple of binary order of magnitude of b.
 func (b Bytes) Floor() Bytes {
-	return b & ^(b.magnitude() - 1)
+	return b.FloorBy(b.magnitude())
 }
 
 // DecimalFloor returns the greatest value less than or equal to b,
 // and is multiple of decimal order of magnitude of b.
 func (b Bytes) DecimalFloor() Bytes {
-	return b - b%b.decimalMagnitude()
+	return b.FloorBy(b.decimalMagnitude())
 }
 
 // Truncate trims b to the greatest value multiple of mag.
 func (b Bytes) Truncate(mag Bytes) Bytes {
+	return b.FloorBy(mag)
+}
+
+// CeilBy returns the least value greater than or equal to b that is multiple of mag.
+// The result wraps around if it exceeds the maximum of Bytes. mag must not be zero.
+func (b Bytes) CeilBy(mag Bytes) Bytes {
+	if mag.IsPowerOfTwo() {
+		return (b + mag - 1) & ^(mag - 1)
+	}
+	switch mod := b % mag; mod {
+	case 0:
+		return b
+	default:
+		return b + mag - mod
+	}
+}
+
+// FloorBy returns the greatest value less than or equal to b that is multiple of mag.
+// mag must not be zero.
+func (b Bytes) FloorBy(mag Bytes) Bytes {
+	if mag.IsPowerOfTwo() {
+		return b & ^(mag - 1)
+	}
 	return b - b%mag
 }
 
-// RoundBy returns the nearest value to b that is multiple of mag.
+// RoundBy returns the nearest value to b that is multiple of mag, halves are rounded up.
+// The result wraps around if it exceeds the maximum of Bytes. mag must not be zero.
 func (b Bytes) RoundBy(mag Bytes) Bytes {
-	switch mag {
-	case B:
-		return b
-	case KiB, MiB, GiB, TiB:
+	if mag.IsPowerOfTwo() {
 		return (b + mag>>1) & ^(mag - 1)
-	default:
-		return (b + mag/2).Truncate(mag)
 	}
+	// b+mag/2 could wrap around even if the result fits
+	if mod := b % mag; mod >= mag-mag/2 {
+		return b - mod + mag
+	}
+	return b - b%mag
 }
 
 // Round returns the nearest value to b that is multiple of binary order of magnitude of b.
@@ -2,7 +2,9 @@ package units
 
 import (
 	"fmt"
+	"math"
 	"testing"
+	"testing/quick"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -443,6 +445,18 @@ func TestBytes_RoundBy(t *testing.T) {
 			mag:  TiB,
 			want: 512*TiB + 1*TiB,
 		},
+		{
+			name: "maximum by a divisor",
+			b:    math.MaxUint64,
+			mag:  3,
+			want: math.MaxUint64,
+		},
+		{
+			name: "near maximum rounded down",
+			b:    math.MaxUint64 - 2,
+			mag:  10,
+			want: math.MaxUint64 - 5,
+		},
 		{
 			name: "round down by kiB",
 			b:    511*TiB + 511*GiB + 511*MiB + 511*KiB + 511*B,
@@ -515,6 +529,24 @@ func TestBytes_RoundBy(t *testing.T) {
 			mag:  TB,
 			want: 499 * TB,
 		},
+		{
+			name: "round up by 4kiB",
+			b:    6 * KiB,
+			mag:  4 * KiB,
+			want: 8 * KiB,
+		},
+		{
+			name: "round down by 64MiB",
+			b:    96*MiB - 1*B,
+			mag:  64 * MiB,
+			want: 64 * MiB,
+		},
+		{
+			name: "round up by 3kiB",
+			b:    4*KiB + 512*B,
+			mag:  3 * KiB,
+			want: 6 * KiB,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -525,6 +557,246 @@ func TestBytes_RoundBy(t *testing.T) {
 	}
 }
 
+func TestBytes_CeilBy(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Bytes
+		mag  Bytes
+		want Bytes
+	}{
+		{
+			name: "zero",
+			b:    0,
+			mag:  4 * KiB,
+			want: 0,
+		},
+		{
+			name: "ceil by B",
+			b:    1*KiB + 1*B,
+			mag:  B,
+			want: 1*KiB + 1*B,
+		},
+		{
+			name: "ceil by 4kiB",
+			b:    4*KiB + 1*B,
+			mag:  4 * KiB,
+			want: 8 * KiB,
+		},
+		{
+			name: "ceil by 64MiB",
+			b:    64 * MiB,
+			mag:  64 * MiB,
+			want: 64 * MiB,
+		},
+		{
+			name: "ceil by kB",
+			b:    1*KB + 1*B,
+			mag:  KB,
+			want: 2 * KB,
+		},
+		{
+			name: "ceil by 3kiB",
+			b:    3*KiB + 1*B,
+			mag:  3 * KiB,
+			want: 6 * KiB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.b.CeilBy(tt.mag); got != tt.want {
+				t.Errorf("CeilBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBytes_FloorBy(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Bytes
+		mag  Bytes
+		want Bytes
+	}{
+		{
+			name: "zero",
+			b:    0,
+			mag:  4 * KiB,
+			want: 0,
+		},
+		{
+			name: "floor by B",
+			b:    1*KiB + 1*B,
+			mag:  B,
+			want: 1*KiB + 1*B,
+		},
+		{
+			name: "floor by 4kiB",
+			b:    8*KiB - 1*B,
+			mag:  4 * KiB,
+			want: 4 * KiB,
+		},
+		{
+			name: "floor by 64MiB",
+			b:    128*MiB + 1*B,
+			mag:  64 * MiB,
+			want: 128 * MiB,
+		},
+		{
+			name: "floor by kB",
+			b:    2*KB - 1*B,
+			mag:  KB,
+			want: 1 * KB,
+		},
+		{
+			name: "floor by 3kiB",
+			b:    6*KiB - 1*B,
+			mag:  3 * KiB,
+			want: 3 * KiB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.b.FloorBy(tt.mag); got != tt.want {
+				t.Errorf("FloorBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// powerOfTwo maps an arbitrary number to a power of two, so that quick.Check covers all of them.
+func powerOfTwo(exp uint8) Bytes {
+	return 1 << (exp % 64)
+}
+
+func TestBytes_CeilBy_quick(t *testing.T) {
+	// the fast path must agree with the division for any power of two
+	f := func(b Bytes, exp uint8) bool {
+		mag := powerOfTwo(exp)
+		got := b.CeilBy(mag)
+		want := b
+		if mod := b % mag; mod != 0 {
+			want = b + mag - mod
+		}
+		return got == want
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	// the result is the least multiple of mag not less than b
+	g := func(b, mag Bytes) bool {
+		if mag == 0 || b > b+mag {
+			return true
+		}
+		got := b.CeilBy(mag)
+		return got%mag == 0 && got >= b && got-b < mag
+	}
+	if err := quick.Check(g, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBytes_FloorBy_quick(t *testing.T) {
+	f := func(b Bytes, exp uint8) bool {
+		mag := powerOfTwo(exp)
+		return b.FloorBy(mag) == b-b%mag
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	// the result is the greatest multiple of mag not greater than b
+	g := func(b, mag Bytes) bool {
+		if mag == 0 {
+			return true
+		}
+		got := b.FloorBy(mag)
+		return got%mag == 0 && got <= b && b-got < mag && got == b.Truncate(mag)
+	}
+	if err := quick.Check(g, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBytes_RoundBy_quick(t *testing.T) {
+	f := func(b Bytes, exp uint8) bool {
+		mag := powerOfTwo(exp)
+		return b.RoundBy(mag) == (b+mag/2)-(b+mag/2)%mag
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	// the result is one of the two nearest multiples, and is not farther than the other one
+	g := func(b, mag Bytes) bool {
+		if mag == 0 || b > b+mag {
+			return true
+		}
+		got := b.RoundBy(mag)
+		floor, ceil := b.FloorBy(mag), b.CeilBy(mag)
+		if got != floor && got != ceil {
+			return false
+		}
+		if got == floor {
+			return b-floor < ceil-b || floor == ceil
+		}
+		return ceil-b <= b-floor
+	}
+	if err := quick.Check(g, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBytes_Round_quick(t *testing.T) {
+	// rounding by the order of magnitude equals to rounding by the magnitude explicitly
+	f := func(b Bytes) bool {
+		return b.Ceil() == b.CeilBy(b.magnitude()) &&
+			b.Floor() == b.FloorBy(b.magnitude()) &&
+			b.Round() == b.RoundBy(b.magnitude()) &&
+			b.DecimalCeil() == b.CeilBy(b.decimalMagnitude()) &&
+			b.DecimalFloor() == b.FloorBy(b.decimalMagnitude()) &&
+			b.DecimalRound() == b.RoundBy(b.decimalMagnitude())
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+var (
+	benchmarkMagnitudes = []Bytes{KiB, 4 * KiB, 64 * MiB, KB, 3 * KiB}
+	benchmarkSink       Bytes
+)
+
+func BenchmarkBytes_CeilBy(b *testing.B) {
+	for _, mag := range benchmarkMagnitudes {
+		b.Run(fmt.Sprintf("%d", mag), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				benchmarkSink = Bytes(i).CeilBy(mag)
+			}
+		})
+	}
+}
+
+func BenchmarkBytes_FloorBy(b *testing.B) {
+	for _, mag := range benchmarkMagnitudes {
+		b.Run(fmt.Sprintf("%d", mag), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				benchmarkSink = Bytes(i).FloorBy(mag)
+			}
+		})
+	}
+}
+
+func BenchmarkBytes_RoundBy(b *testing.B) {
+	for _, mag := range benchmarkMagnitudes {
+		b.Run(fmt.Sprintf("%d", mag), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				benchmarkSink = Bytes(i).RoundBy(mag)
+			}
+		})
+	}
+}
+
 func TestBytes_Format(t *testing.T) {
 	formatToBinaryMagnitude := map[string]Bytes{"b": B, "k": KiB, "m": MiB, "g": GiB, "t": TiB}
 	formatToDecimalMagnitude := map[string]Bytes{"b": B, "k": KB, "m": MB, "g": GB, "t": TB}
@@ -0,0 +1,254 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNotAligned is matched by *AlignmentError.
+var ErrNotAligned = errors.New("units: not aligned")
+
+// AlignmentError is returned when a size is not a multiple of the required alignment.
+type AlignmentError struct {
+	Value, Align Bytes
+}
+
+func (e *AlignmentError) Error() string {
+	return fmt.Sprintf("units: %f is not a multiple of %f", e.Value, e.Align)
+}
+
+// Is reports whether target is ErrNotAligned.
+func (e *AlignmentError) Is(target error) bool {
+	return target == ErrNotAligned
+}
+
+// FieldError is an invalid field found by Validate or LoadEnv.
+type FieldError struct {
+	// Path is the path of the field from the validated struct, like "Cache.Shards[2].Size".
+	Path string
+	// Err is a *RangeError, an *AlignmentError, an *EnvError, or the error of an invalid tag.
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors is all the invalid fields found by Validate or LoadEnv.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+var bytesType = reflect.TypeOf(Bytes(0))
+
+// Validate checks the Bytes fields of struct v by their "units" tags, like
+//
+//	PartSize units.Bytes `units:"min=5MiB,max=5GiB,align=4KiB"`
+//
+// where each of min, max and align is optional and parsed by Parse. A tag on a slice, array or map of Bytes
+// applies to every element. Nested structs, pointers, slices, arrays and maps of them are validated
+// recursively, nil pointers are skipped and so are pointers already walked through, so cycles end.
+// Map elements are reported with paths like "Volumes[data]".
+//
+// The returned error is ValidationErrors listing every invalid field, or nil if all are valid.
+func Validate(v interface{}) error {
+	w := validator{visited: visitedSet{}}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() && w.visited.first(rv) {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("units: cannot validate %T, want a struct", v)
+	}
+	w.validateStruct(rv, "")
+	if len(w.errs) > 0 {
+		return w.errs
+	}
+	return nil
+}
+
+// validator walks a struct for Validate.
+type validator struct {
+	errs    ValidationErrors
+	visited visitedSet
+}
+
+func (v *validator) validateStruct(rv reflect.Value, path string) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		tag, tagged := field.Tag.Lookup("units")
+		if !tagged {
+			v.validateNested(rv.Field(i), fieldPath)
+			continue
+		}
+		c, err := parseConstraint(tag)
+		if err != nil {
+			v.errs = append(v.errs, &FieldError{Path: fieldPath, Err: err})
+			continue
+		}
+		v.validateTagged(rv.Field(i), fieldPath, c)
+	}
+}
+
+// validateNested validates the structs in rv, which is not tagged.
+func (v *validator) validateNested(rv reflect.Value, path string) {
+	if !v.visited.first(rv) {
+		return
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !rv.IsNil() {
+			v.validateNested(rv.Elem(), path)
+		}
+	case reflect.Struct:
+		v.validateStruct(rv, path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			v.validateNested(rv.Index(i), path+"["+strconv.Itoa(i)+"]")
+		}
+	case reflect.Map:
+		for _, key := range sortedMapKeys(rv) {
+			v.validateNested(rv.MapIndex(key), fmt.Sprintf("%s[%v]", path, key))
+		}
+	}
+}
+
+// validateTagged checks the Bytes in rv against c.
+func (v *validator) validateTagged(rv reflect.Value, path string, c constraint) {
+	if !v.visited.first(rv) {
+		return
+	}
+	switch {
+	case rv.Type() == bytesType:
+		if err := c.check(Bytes(rv.Uint())); err != nil {
+			v.errs = append(v.errs, &FieldError{Path: path, Err: err})
+		}
+	case rv.Kind() == reflect.Ptr:
+		if !rv.IsNil() {
+			v.validateTagged(rv.Elem(), path, c)
+		}
+	case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			v.validateTagged(rv.Index(i), path+"["+strconv.Itoa(i)+"]", c)
+		}
+	case rv.Kind() == reflect.Map:
+		for _, key := range sortedMapKeys(rv) {
+			v.validateTagged(rv.MapIndex(key), fmt.Sprintf("%s[%v]", path, key), c)
+		}
+	default:
+		v.errs = append(v.errs, &FieldError{Path: path, Err: fmt.Errorf("units: tag on unsupported type %s", rv.Type())})
+	}
+}
+
+// visit identifies a pointer, map or slice being walked. The length tells apart slices sharing an array.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// visitedSet records the references walked through, so that cyclic data is walked only once.
+type visitedSet map[visit]struct{}
+
+// first records rv if it is a reference, and reports whether it has not been recorded before.
+func (s visitedSet) first(rv reflect.Value) bool {
+	var key visit
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if rv.IsNil() {
+			return true
+		}
+		key = visit{ptr: rv.Pointer(), typ: rv.Type()}
+	case reflect.Slice:
+		if rv.Len() == 0 {
+			return true
+		}
+		key = visit{ptr: rv.Pointer(), typ: rv.Type(), len: rv.Len()}
+	default:
+		return true
+	}
+	if _, ok := s[key]; ok {
+		return false
+	}
+	s[key] = struct{}{}
+	return true
+}
+
+// sortedMapKeys returns the keys of map rv sorted by their text, so that the errors are in a stable order.
+func sortedMapKeys(rv reflect.Value) []reflect.Value {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+// constraint is a parsed "units" tag.
+type constraint struct {
+	Range
+	align Bytes
+}
+
+func parseConstraint(tag string) (constraint, error) {
+	c := constraint{Range: Range{Max: math.MaxUint64}}
+	for _, option := range strings.Split(tag, ",") {
+		i := strings.Index(option, "=")
+		if i < 0 {
+			return c, fmt.Errorf("units: invalid tag option %q", option)
+		}
+		b, err := Parse(option[i+1:])
+		if err != nil {
+			return c, err
+		}
+		switch key := strings.TrimSpace(option[:i]); key {
+		case "min":
+			c.Min = b
+		case "max":
+			c.Max = b
+		case "align":
+			if b == 0 {
+				return c, ErrInvalidAlignment
+			}
+			c.align = b
+		default:
+			return c, fmt.Errorf("units: unknown tag option %q", key)
+		}
+	}
+	if !c.Valid() {
+		return c, ErrInvalidRange
+	}
+	return c, nil
+}
+
+func (c constraint) check(b Bytes) error {
+	if err := c.Check(b); err != nil {
+		return err
+	}
+	if c.align != 0 && !b.IsAligned(c.align) {
+		return &AlignmentError{Value: b, Align: c.align}
+	}
+	return nil
+}
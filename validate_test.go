@@ -0,0 +1,181 @@
+package units
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testShard struct {
+	Size Bytes `units:"min=1MiB,max=1GiB,align=4KiB"`
+}
+
+type testConfig struct {
+	PartSize Bytes   `units:"min=5MiB,max=5GiB"`
+	Buffers  []Bytes `units:"align=4k"`
+	Cache    struct {
+		Max    *Bytes `units:"max=10GiB"`
+		Shards []testShard
+	}
+	Primary *testShard
+	Backups [2]testShard
+	Note    string
+	limit   Bytes `units:"max=1"`
+}
+
+func validTestConfig() testConfig {
+	var c testConfig
+	c.PartSize = 8 * MiB
+	c.Buffers = []Bytes{4 * KiB, 64 * KiB}
+	max := 10 * GiB
+	c.Cache.Max = &max
+	c.Cache.Shards = []testShard{{Size: MiB}, {Size: GiB}}
+	c.Primary = &testShard{Size: 2 * MiB}
+	c.Backups = [2]testShard{{Size: MiB}, {Size: MiB}}
+	c.limit = 2
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	c := validTestConfig()
+	assert.NoError(t, Validate(c))
+	assert.NoError(t, Validate(&c))
+
+	c.Primary = nil
+	c.Cache.Max = nil
+	assert.NoError(t, Validate(&c))
+}
+
+func TestValidate_Errors(t *testing.T) {
+	c := validTestConfig()
+	c.PartSize = MiB
+	c.Buffers[1] = 5000
+	*c.Cache.Max = 11 * GiB
+	c.Cache.Shards[1].Size = GiB + 4*KiB
+	c.Primary.Size = MiB + 1
+	c.Backups[0].Size = 4 * KiB
+
+	err := Validate(&c)
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	paths := make([]string, len(errs))
+	for i, e := range errs {
+		paths[i] = e.Path
+	}
+	assert.Equal(t, []string{
+		"PartSize",
+		"Buffers[1]",
+		"Cache.Max",
+		"Cache.Shards[1].Size",
+		"Primary.Size",
+		"Backups[0].Size",
+	}, paths)
+
+	assert.EqualError(t, errs[0], "PartSize: units: 1.0MiB is less than the minimum 5.0MiB")
+	assert.True(t, errors.Is(errs[0], ErrOutOfRange))
+	assert.EqualError(t, errs[1], "Buffers[1]: units: 4.9kiB is not a multiple of 4.0kiB")
+	assert.True(t, errors.Is(errs[1], ErrNotAligned))
+	assert.True(t, errors.Is(errs[4], ErrNotAligned))
+	assert.Contains(t, err.Error(), "; Cache.Max: units: 11.0GiB is greater than the maximum 10.0GiB; ")
+}
+
+func TestValidate_InvalidTags(t *testing.T) {
+	tests := []struct {
+		v       interface{}
+		wantErr error
+	}{
+		{v: struct {
+			A Bytes `units:"min=1XiB"`
+		}{}, wantErr: ErrSyntax},
+		{v: struct {
+			A Bytes `units:"min"`
+		}{}},
+		{v: struct {
+			A Bytes `units:"size=1MiB"`
+		}{}},
+		{v: struct {
+			A Bytes `units:"align=0"`
+		}{}, wantErr: ErrInvalidAlignment},
+		{v: struct {
+			A Bytes `units:"min=2MiB,max=1MiB"`
+		}{}, wantErr: ErrInvalidRange},
+		{v: struct {
+			A int `units:"max=1MiB"`
+		}{}},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.v)
+		var errs ValidationErrors
+		require.True(t, errors.As(err, &errs), "%T", tt.v)
+		require.Len(t, errs, 1)
+		assert.Equal(t, "A", errs[0].Path)
+		if tt.wantErr != nil {
+			assert.True(t, errors.Is(errs[0], tt.wantErr), "%v", errs[0])
+		}
+	}
+}
+
+func TestValidate_NotStruct(t *testing.T) {
+	assert.EqualError(t, Validate(MiB), "units: cannot validate units.Bytes, want a struct")
+	assert.Error(t, Validate(nil))
+	assert.Error(t, Validate((*testConfig)(nil)))
+}
+
+func TestValidate_maps(t *testing.T) {
+	c := struct {
+		Volumes map[string]testShard
+		Quotas  map[string]Bytes `units:"max=1GiB"`
+		Nested  map[int][]*testShard
+	}{
+		Volumes: map[string]testShard{"data": {Size: KiB}, "logs": {Size: MiB}, "tmp": {Size: 2 * GiB}},
+		Quotas:  map[string]Bytes{"alice": MiB, "bob": 2 * GiB},
+		Nested:  map[int][]*testShard{7: {{Size: MiB}, {Size: 1}}},
+	}
+	err := Validate(c)
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	paths := make([]string, len(errs))
+	for i, e := range errs {
+		paths[i] = e.Path
+	}
+	assert.Equal(t, []string{
+		"Volumes[data].Size",
+		"Volumes[tmp].Size",
+		"Quotas[bob]",
+		"Nested[7][1].Size",
+	}, paths)
+	assert.True(t, errors.Is(errs[0], ErrOutOfRange))
+}
+
+type testNode struct {
+	Size     Bytes `units:"max=1kiB"`
+	Next     *testNode
+	Children []testNode
+	Any      interface{}
+}
+
+func TestValidate_cycle(t *testing.T) {
+	n := &testNode{Size: 2048}
+	n.Next = n
+	err := Validate(n)
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Size", errs[0].Path)
+
+	a, b := &testNode{}, &testNode{Size: 2048}
+	a.Next, b.Next = b, a
+	a.Children = []testNode{{Any: a}}
+	a.Any = a.Children
+	err = Validate(a)
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Next.Size", errs[0].Path)
+
+	type list []interface{}
+	l := list{nil}
+	l[0] = l
+	assert.NoError(t, Validate(struct{ L list }{l}))
+}